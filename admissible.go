@@ -0,0 +1,77 @@
+package astar
+
+import (
+	"context"
+	"fmt"
+)
+
+// AdmissibilityError reports an expanded node whose H was higher than its cheapest cost
+// to the end node, it is returned with the found path if Config.CheckAdmissibility is set
+// and errors.Is(err, ErrInadmissibleHeuristic) reports true for it
+type AdmissibilityError struct {
+	// Node is the first such node in the order of the expansion, Node.H() is its heuristic
+	Node Node
+	// Cost is the cheapest cost from Node to the end node
+	Cost int
+}
+
+// Error returns the message of ErrInadmissibleHeuristic with the node and both costs
+func (e *AdmissibilityError) Error() string {
+	return fmt.Sprintf("%v, node X:%d Y:%d has H %d but cost %d", ErrInadmissibleHeuristic, e.Node.X, e.Node.Y, e.Node.h, e.Cost)
+}
+
+// Unwrap returns ErrInadmissibleHeuristic
+func (e *AdmissibilityError) Unwrap() error {
+	return ErrInadmissibleHeuristic
+}
+
+// recordExpanded returns an onExpand callback which appends the nodes to expanded
+// and calls onExpand if it is set
+func recordExpanded(expanded *[]Node, onExpand func(node Node)) func(node Node) {
+	return func(node Node) {
+		*expanded = append(*expanded, node)
+		if onExpand != nil {
+			onExpand(node)
+		}
+	}
+}
+
+// checkAdmissibility compares the H of the expanded nodes with their cheapest cost to goalNode,
+// the last node of the found path, which is computed by a reverse flood from goalNode
+func (a *PathFinder) checkAdmissibility(cancelCtx context.Context, ctx IContext, expanded []Node, goalNode, endNode Node) error {
+	// H is the distance to the end node, not to a node IsNearEnough accepted
+	if goalNode.X != endNode.X || goalNode.Y != endNode.Y {
+		return nil
+	}
+
+	// a node whose cost is above the highest H cannot be a violation,
+	// so the flood stops there
+	maxH := 0
+	for _, node := range expanded {
+		if node.h > maxH {
+			maxH = node.h
+		}
+	}
+	costs := make(map[[2]int]int)
+	opts := searchOptions{
+		maxSteps:  StepsNoLimit,
+		flood:     true,
+		backward:  true,
+		costLimit: true,
+		maxCost:   maxH,
+		onExpand: func(node Node) {
+			costs[nodeKey(node)] = node.g
+		},
+	}
+	// the check is skipped if the flood cannot run, e.g. from a blocked end node
+	if _, err := a.findPathLocked(cancelCtx, ctx, goalNode, goalNode, opts); err != nil && err != ErrorNoPath {
+		return nil
+	}
+
+	for _, node := range expanded {
+		if cost, ok := costs[nodeKey(node)]; ok && node.h > cost {
+			return &AdmissibilityError{Node: node, Cost: cost}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package astar
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAstar_FindPathCheckAdmissibility(t *testing.T) {
+	// . . . . E
+	// . X X X .
+	// S . . . .
+	obstacleNodes := []Node{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 3, Y: 1}}
+	startNode, endNode := Node{X: 0, Y: 0}, Node{X: 4, Y: 2}
+
+	a, err := New(Config{GridWidth: 5, GridHeight: 3, InvalidNodes: obstacleNodes, Heuristic: ManhattanDistance, CheckAdmissibility: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if _, err := a.FindPath(nil, startNode, endNode); err != nil {
+		t.Error("the manhattan distance should be admissible: ", err)
+	}
+
+	overestimate := func(nodeA, nodeB Node) int {
+		return 3 * ManhattanDistance(nodeA, nodeB)
+	}
+	a, err = New(Config{GridWidth: 5, GridHeight: 3, InvalidNodes: obstacleNodes, Heuristic: overestimate, CheckAdmissibility: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, err := a.FindPath(nil, startNode, endNode)
+	var admissibilityErr *AdmissibilityError
+	if !errors.As(err, &admissibilityErr) || !errors.Is(err, ErrInadmissibleHeuristic) {
+		t.Fatal("the overestimation should be reported: ", err)
+	}
+	if admissibilityErr.Node.H() <= admissibilityErr.Cost {
+		t.Error("the reported node should overestimate: ", admissibilityErr.Node.H(), admissibilityErr.Cost)
+	}
+	if len(foundPath) != 7 {
+		t.Error("the path should still be returned: ", foundPath)
+	}
+
+	// the stepper runs the same check after its last step
+	st, err := a.NewSearch(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	for {
+		done, err := st.Step()
+		if !done {
+			continue
+		}
+		if !errors.Is(err, ErrInadmissibleHeuristic) {
+			t.Error("the stepper should report the overestimation: ", err)
+		}
+		break
+	}
+	if len(st.Path()) != 7 {
+		t.Error("the stepper should still return the path: ", st.Path())
+	}
+
+	// without the check the same search succeeds silently
+	a, err = New(Config{GridWidth: 5, GridHeight: 3, InvalidNodes: obstacleNodes, Heuristic: overestimate})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if _, err := a.FindPath(nil, startNode, endNode); err != nil {
+		t.Error("the check should be disabled by default: ", err)
+	}
+}
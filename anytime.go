@@ -0,0 +1,71 @@
+package astar
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// the heuristic weights of FindPathAnytime, the first search is the fastest one
+const (
+	anytimeStartWeight = 3
+	anytimeWeightStep  = 0.5
+)
+
+// FindPathAnytime runs weighted searches with decreasing heuristic weights
+// until the deadline passes and returns the cheapest path found so far
+//
+// The first search uses a weight of 3 and finds a path quickly, every following search
+// lowers the weight by 0.5 down to the configured HeuristicWeight and only opens nodes
+// whose cost stays below the best path, so it is pruned by the earlier result.
+// Once the search with the final weight is done the path is returned without waiting for the deadline,
+// with the default weight of 1 it is then as short as the one of FindPath.
+// If the deadline passes before the first path is found it returns context.DeadlineExceeded
+func (a *PathFinder) FindPathAnytime(ctx IContext, startNode, endNode Node, deadline time.Time) ([]Node, error) {
+	cancelCtx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	finalWeight := a.config.HeuristicWeight
+	weight := float64(anytimeStartWeight)
+	if weight < finalWeight || a.config.DisableHeuristic {
+		weight = finalWeight
+	}
+
+	var bestPath []Node
+	for {
+		if err := cancelCtx.Err(); err != nil {
+			if bestPath != nil {
+				return bestPath, nil
+			}
+			return nil, err
+		}
+
+		opts := searchOptions{maxSteps: StepsNoLimit, weight: weight}
+		if bestPath != nil {
+			// only a cheaper path is of interest
+			opts.costLimit = true
+			opts.maxCost = bestPath[len(bestPath)-1].g - 1
+		}
+
+		foundPath, err := a.doFindPath(cancelCtx, ctx, startNode, endNode, opts)
+		switch {
+		case err == nil:
+			bestPath = foundPath
+		case errors.Is(err, ErrorNoPath) && bestPath != nil:
+			// there is no cheaper path for this weight
+		case err == cancelCtx.Err() && bestPath != nil:
+			// the deadline passed during the search
+			return bestPath, nil
+		default:
+			return nil, err
+		}
+
+		if weight <= finalWeight {
+			return bestPath, nil
+		}
+		weight -= anytimeWeightStep
+		if weight < finalWeight {
+			weight = finalWeight
+		}
+	}
+}
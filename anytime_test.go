@@ -0,0 +1,67 @@
+package astar
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAstar_FindPathAnytime(t *testing.T) {
+	startNode := Node{X: 0, Y: 2}
+	endNode := Node{X: 19, Y: 2}
+
+	// a strip of mud on the direct line, the weighted searches cross it
+	var weightedNodes []Node
+	for x := 5; x < 15; x++ {
+		for y := 0; y < 6; y++ {
+			weightedNodes = append(weightedNodes, Node{X: x, Y: y, Weighting: 10})
+		}
+	}
+	config := Config{GridWidth: 20, GridHeight: 20, WeightedNodes: weightedNodes, AllowDiagonal: true, HeuristicWeight: anytimeStartWeight}
+
+	a, err := New(config)
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	_, weightedCost, err := a.FindPathWithCost(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+
+	config.HeuristicWeight = 0
+	a, err = New(config)
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	_, wantCost, err := a.FindPathWithCost(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if weightedCost <= wantCost {
+		t.Fatal("the weighted path should cross the mud: ", weightedCost, wantCost)
+	}
+
+	foundPath, err := a.FindPathAnytime(nil, startNode, endNode, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if cost := foundPath[len(foundPath)-1].g; cost != wantCost {
+		t.Error("final path should be optimal: ", cost, wantCost)
+	}
+	if foundPath[0].X != startNode.X || foundPath[0].Y != startNode.Y {
+		t.Error("path should start at the start node: ", foundPath)
+	}
+
+	// no time for a first path
+	if _, err = a.FindPathAnytime(nil, startNode, endNode, time.Now()); err != context.DeadlineExceeded {
+		t.Error("deadline should be exceeded", err)
+	}
+
+	// a walled in end node has no path for any weight
+	a.AddObstacle(18, 19)
+	a.AddObstacle(18, 18)
+	a.AddObstacle(19, 18)
+	if _, err = a.FindPathAnytime(nil, startNode, Node{X: 19, Y: 19}, time.Now().Add(time.Minute)); err != ErrorNoPath {
+		t.Error("there should be no path", err)
+	}
+}
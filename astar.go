@@ -1,31 +1,365 @@
 package astar
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math"
+	"sync"
+	"sync/atomic"
 )
 
 var (
 	ErrorNoPath = errors.New("no path found")
+	// ErrStepLimitReached is returned if the search expanded maxSteps nodes
+	// before it reached the goal
+	ErrStepLimitReached = errors.New("step limit reached")
+	// ErrSearchStopped is returned by FindPathFunc if the callback
+	// stopped the search before it reached the goal
+	ErrSearchStopped = errors.New("search stopped")
+	// ErrOpenListOverflow is returned if the open list grew beyond Config.MaxOpenNodes
+	ErrOpenListOverflow = errors.New("open list overflow")
+	// ErrOutOfBounds is returned if the start or the end node is not on the grid
+	ErrOutOfBounds = errors.New("node out of bounds")
+	// ErrStartBlocked is returned if the start node is an obstacle
+	ErrStartBlocked = errors.New("start node is blocked")
+	// ErrEndBlocked is returned if the end node is an obstacle
+	// and ctx.IsNearEnough does not accept it either
+	ErrEndBlocked = errors.New("end node is blocked")
+	// ErrUnboundedGrid is returned by the methods which keep a value for every cell of the grid
+	// if Config.Unbounded is set
+	ErrUnboundedGrid = errors.New("not supported on an unbounded grid")
+	// ErrGridWidth and ErrGridHeight are returned by New if the grid has no column or no row,
+	// a single one is fine, e.g. for a corridor
+	ErrGridWidth  = errors.New("GridWidth must be min 1")
+	ErrGridHeight = errors.New("GridHeight must be min 1")
+	// ErrInadmissibleHeuristic is wrapped by the *AdmissibilityError of Config.CheckAdmissibility
+	ErrInadmissibleHeuristic = errors.New("heuristic overestimates the cost")
 )
 
+// NoPathError describes a search which expanded every reachable node without reaching the goal,
+// it is returned instead of ErrorNoPath if Config.NoPathDetails is set and
+// errors.Is(err, ErrorNoPath) reports true for it
+type NoPathError struct {
+	// ExpandedCount is the number of nodes moved to the closedList
+	ExpandedCount int
+	// ClosestNode is the expanded node with the smallest heuristic distance to the goal
+	ClosestNode Node
+}
+
+// Error returns the message of ErrorNoPath with the diagnostics
+func (e *NoPathError) Error() string {
+	return fmt.Sprintf("%v, expanded %d nodes, closest node X:%d Y:%d", ErrorNoPath, e.ExpandedCount, e.ClosestNode.X, e.ClosestNode.Y)
+}
+
+// Unwrap returns ErrorNoPath
+func (e *NoPathError) Unwrap() error {
+	return ErrorNoPath
+}
+
 const (
 	StepsNoLimit = -1
 )
 
+// TieBreaking decides which node of the open list is expanded first
+// if several nodes share the same F value. Nodes which are still equal
+// are ordered by X and then Y, so the same query always returns the same path
+type TieBreaking int
+
+const (
+	// TieBreakLowerH prefers the node closer to the goal, this is the default
+	TieBreakLowerH TieBreaking = iota
+	// TieBreakNone does not compare H, only the coordinates break ties
+	TieBreakNone
+	// TieBreakCrossProduct prefers the lower H and then the node
+	// closer to the straight line from start to goal
+	TieBreakCrossProduct
+)
+
+// cancelCheckSteps is the number of expansions between two checks
+// of the cancellation context
+const cancelCheckSteps = 64
+
+// default step costs used when diagonal movement is allowed
+// 14/10 is the integer approximation of sqrt(2)
+const (
+	costStraight = 10
+	costDiagonal = 14
+)
+
+// infCost marks a cell which cannot reach the goal
+// it is the largest int so costs of huge grids stay below it
+const infCost = int(^uint(0) >> 1)
+
+// WeightImpassable is the largest Weighting, costs are added with saturation
+// so a node with this weight or a path whose cost reaches it is never taken
+const WeightImpassable = infCost
+
+// DiagonalPolicy decides which diagonal steps are allowed beside blocked orthogonal cells,
+// the two cells which share an edge with both the current and the diagonal cell
+type DiagonalPolicy int
+
+const (
+	// DiagonalAlways allows every diagonal step onto a free cell, this is the default.
+	// A unit may even squeeze between two blocked orthogonal cells
+	DiagonalAlways DiagonalPolicy = iota
+	// DiagonalOnlyWhenNoObstacles allows a diagonal step only if both orthogonal cells are free,
+	// so a unit does not pass the corner of a single wall. It is the rule of DisallowCornerCutting
+	DiagonalOnlyWhenNoObstacles
+	// DiagonalNoCornerCutting rejects a diagonal step if both orthogonal cells are blocked,
+	// a unit may pass the corner of a single wall but not cut through the closed corner of two
+	DiagonalNoCornerCutting
+)
+
+// GridType selects the shape of the grid cells
+type GridType int
+
+const (
+	// GridSquare is the default grid of square cells with 4 or 8 neighbors
+	GridSquare GridType = iota
+	// GridHex is a grid of hexagons in axial coordinates with 6 neighbors,
+	// X is the q and Y the r axis
+	GridHex
+)
+
+// hexOffsets are the axial X/Y deltas of the six hex neighbors
+var hexOffsets = [6][2]int{
+	{1, 0},
+	{-1, 0},
+	{0, 1},
+	{0, -1},
+	{1, -1},
+	{-1, 1},
+}
+
+// orthogonalOffsets are the X/Y deltas of the up, down, left and right neighbors
+var orthogonalOffsets = [4][2]int{
+	{0, 1},
+	{0, -1},
+	{-1, 0},
+	{1, 0},
+}
+
+// diagonalOffsets are the X/Y deltas of the four diagonal neighbors
+var diagonalOffsets = [4][2]int{
+	{1, 1},
+	{-1, 1},
+	{1, -1},
+	{-1, -1},
+}
+
+// Rect is a rectangle of cells, both corners are included
+type Rect struct {
+	MinX, MinY, MaxX, MaxY int
+}
+
+// Contains checks if the cell is inside the rectangle
+func (r Rect) Contains(x, y int) bool {
+	return x >= r.MinX && x <= r.MaxX && y >= r.MinY && y <= r.MaxY
+}
+
+// WeightedRect is a rectangular cost zone, e.g. a swamp,
+// entering any cell inside Bounds costs Weighting extra
+type WeightedRect struct {
+	Bounds    Rect
+	Weighting int
+}
+
 // Config holds important settings
 // to perform the calculation
 //
-// GridWidth and GridHeight are required and represents
-// the size of the grid
+// GridWidth and GridHeight are required unless the grid is Unbounded and represents
+// the size of the grid, a width or height of 1 makes a corridor
+// OriginX and OriginY are the coordinates of the lower left cell, 0 by default,
+// so the grid spans OriginX to OriginX+GridWidth-1 and OriginY to OriginY+GridHeight-1
 //
 // InvalidNodes can be used to add not accessible nodes like obstacles etc.
-// WeightedNodes can be used to add nodes to be avoided like mud or mountains
+// WeightedNodes can be used to add nodes to be avoided like mud or mountains,
+// the costs saturate instead of overflowing and a Weighting of WeightImpassable blocks the node
+//
+// All other settings are optional, their zero values keep the default behavior.
+// FindPathJPS, FindPathTheta and FindPathBidirectional fall back to FindPath for the settings
+// their own searches do not support, their docs list them
 type Config struct {
 	GridWidth, GridHeight int
+	OriginX, OriginY      int
 	InvalidNodes          []Node
 	WeightedNodes         []Node
+
+	// AllowDiagonal enables 8-directional movement,
+	// diagonal steps cost more than orthogonal ones
+	AllowDiagonal bool
+	// DisallowCornerCutting rejects a diagonal step if one of the two
+	// orthogonal cells next to it is blocked, like DiagonalOnlyWhenNoObstacles
+	DisallowCornerCutting bool
+	// DiagonalPolicy selects the rule for diagonal steps beside blocked cells,
+	// it is ignored without AllowDiagonal. The line of sight of FindPathTheta and SmoothPath
+	// never passes between two blocked cells
+	DiagonalPolicy DiagonalPolicy
+	// OrthogonalCost and DiagonalCost are the costs of a single step,
+	// 0 means the default of 10 and 14 with AllowDiagonal and 1 without it.
+	// H is scaled by OrthogonalCost, a DiagonalCost below sqrt(2) times of it
+	// can make the diagonal heuristics overestimate
+	OrthogonalCost, DiagonalCost int
+
+	// Heuristic replaces the default heuristic if set, by default it is the manhattan distance,
+	// with AllowDiagonal the Chebyshev distance and with GridHex the HexDistance
+	Heuristic Heuristic
+	// MinMoveCost is the cheapest cost of an orthogonal step anywhere on the map, OrthogonalCost
+	// plus the smallest weight of all cells, e.g. 5 with the default OrthogonalCost of 1
+	// if every cell has a Weighting of at least 4.
+	// If it is above OrthogonalCost, H adds MinMoveCost - OrthogonalCost for every step
+	// between the nodes, so H is no longer much lower than the cost on heavily weighted maps
+	// and the search expands fewer nodes. A value above the cost of any step makes H overestimate.
+	// PathFinderF does not use it
+	MinMoveCost int
+	// DisableHeuristic sets H to 0 so the search behaves like Dijkstra's algorithm,
+	// it expands more nodes and is slower but the path is always optimal
+	DisableHeuristic bool
+	// HeuristicWeight scales H in F = G + weight * H, values above 1 expand fewer nodes
+	// but the path may not be optimal. 0 means the default of 1, negative values are clamped to 0
+	HeuristicWeight float64
+	// TieBreaking selects how nodes with equal F are ordered in the open list
+	TieBreaking TieBreaking
+
+	// WeightedRegions are cost zones for large areas, the Weightings of all regions
+	// containing a cell are added to the Weighting of its WeightedNodes.
+	// A region is stored once instead of a WeightedNode for each of its cells
+	WeightedRegions []WeightedRect
+	// CostFunc returns the extra cost of entering a cell,
+	// if set it replaces the WeightedNodes and the WeightedRegions
+	CostFunc func(x, y int) int
+	// MoveCost returns the cost of the step between two neighboring cells if set,
+	// e.g. uphill steps cost more than downhill ones. It replaces the OrthogonalCost
+	// and DiagonalCost as well as the CostFunc, WeightedNodes and WeightedRegions, the Weighting of a
+	// Neighbors candidate is still added. Negative costs are clamped to 0.
+	// H is scaled by OrthogonalCost, so no step may cost less than OrthogonalCost
+	// times the heuristic distance it covers, otherwise the path may not be optimal
+	MoveCost func(from, to Node) int
+
+	// GridType selects square or hex cells, with GridHex the bounds form
+	// a rhombus in axial coordinates, AllowDiagonal is ignored
+	// and the default heuristic is the HexDistance
+	GridType GridType
+
+	// Neighbors replaces the 4/8-directional neighbor model if set,
+	// e.g. to connect distant cells with portals. The returned candidates
+	// must not be out of bounds or blocked, otherwise they are dropped.
+	// The Weighting of a candidate is added to the cost of entering it.
+	// With portals the heuristic may overestimate, so the path is not guaranteed to be optimal.
+	// The IncrementalPlanner expects the neighbors to be symmetric
+	Neighbors func(ctx IContext, node Node) []Node
+
+	// PartialPathOnStepLimit makes FindPathEx return the path to the last
+	// expanded node together with ErrStepLimitReached instead of nil
+	PartialPathOnStepLimit bool
+
+	// AllowBlockedEndpoints lets a search start or end on a blocked cell,
+	// e.g. for a unit standing in a wall that was built after it.
+	// The start is never checked by isAccessible, only its neighbors are,
+	// so the first step out of it is valid if the target cell is accessible.
+	// A blocked end node passes isAccessible as the last step of FindPath and its
+	// variants, the goals of FindPathMulti and all other cells stay blocked.
+	// Both nodes must still be in bounds
+	AllowBlockedEndpoints bool
+	// SnapBlockedEnd replaces an end node which cannot be entered, e.g. a click on a wall
+	// or outside of the grid, by its NearestWalkable cell before FindPath and its variants search.
+	// The path ends at that cell then, with AllowBlockedEndpoints set the end node is snapped as well.
+	// CountShortestPaths counts the paths to the given end node only
+	SnapBlockedEnd bool
+
+	// SearchBounds limits the searches to the cells inside the rectangle if set,
+	// isAccessible rejects all cells outside of it, so a local route on a huge grid
+	// expands only the cells of the window. It uses the same coordinates as the nodes.
+	// A start or end node outside of it is no error of the input, the search returns ErrorNoPath
+	SearchBounds *Rect
+
+	// BlockedEdges are thin walls between two neighboring cells which are both walkable,
+	// the step between the cells is rejected in both directions. SmoothPath returns the path unchanged
+	BlockedEdges []Edge
+
+	// RegionCheck makes FindPath and its variants return ErrorNoPath without searching
+	// if SameRegion reports that the end node cannot be reached from the start node.
+	// It is skipped for searches with an IContext, IsNearEnough may accept a node of another region,
+	// and with GoalRadius or AllowBlockedEndpoints
+	RegionCheck bool
+
+	// Unbounded takes the bounds of the world from the ctx of the searches instead of
+	// GridWidth and GridHeight, e.g. for terrain which is streamed in chunks. A cell is
+	// in bounds if the ctx implements IBoundsContext and IsInBounds accepts it, with another
+	// ctx every cell is, so an unreachable goal needs SearchBounds or a step limit.
+	// The methods which keep a value for every cell, DistanceField, FlowField, DistanceFieldParallel,
+	// CountShortestPaths, PathFinderF, IncrementalPlanner and the regions, do not support it and it cannot be
+	// combined with WrapEdges or RegionCheck
+	Unbounded bool
+
+	// NoPathDetails makes FindPath and its variants return a *NoPathError with the number
+	// of expanded nodes and the closest node to the goal if the search ran out of nodes,
+	// use errors.Is(err, ErrorNoPath) or errors.As to check for it
+	NoPathDetails bool
+
+	// CheckAdmissibility is a debug check for a custom Heuristic: after FindPath and its variants
+	// found a path, a reverse flood from the end node computes the cheapest cost to it from
+	// every expanded node, and if one H was higher the path is returned with an *AdmissibilityError.
+	// The flood costs about as much as the search, so it is meant for development only.
+	// H is compared without HeuristicWeight, searches which end at another node than the end node,
+	// FindPathJPS, FindPathTheta and FindPathBidirectional are not checked
+	CheckAdmissibility bool
+
+	// GoalRadius lets FindPath and its variants stop at the first node within this distance
+	// of the end node if no IContext is passed, like IsNearEnough does. The distance is
+	// the Chebyshev distance with AllowDiagonal, the HexDistance on hex grids and the manhattan one otherwise.
+	// A blocked end node is accepted then, the path ends next to it
+	GoalRadius int
+
+	// WrapEdges joins the opposite borders of the grid like a torus, a step over the right border
+	// leads to the left column and a step over the top border to the bottom row.
+	// H uses the shorter way around, BlockedEdges may block the steps over the seam.
+	// Consecutive path nodes on both sides of the seam are neighbors then,
+	// SmoothPath returns the path unchanged
+	WrapEdges bool
+
+	// BucketOpenList keeps the open list in one bucket per F value instead of a binary heap
+	// (Dial's algorithm), adding and taking a node does not depend on the size of the open list.
+	// It suits grids with small integer step costs, nodes with equal F are taken in LIFO order
+	// instead of by TieBreaking, so the path may differ from the one of the heap but has the same cost.
+	// The buckets cover a window of F values which moves up with the cheapest open node,
+	// nodes with an F far above it, e.g. behind big Weightings, fall back to a heap
+	BucketOpenList bool
+	// OpenListFactory creates the open list of every search if set, e.g. to try an own
+	// priority queue. TieBreaking is up to the queue, it cannot be combined with BucketOpenList
+	OpenListFactory func() PriorityQueue
+
+	// AgentRadius inflates the obstacles for units bigger than one cell, 0 is a point-sized unit.
+	// A unit covers the cells within this Chebyshev distance of its cell, on hex grids within
+	// the HexDistance, and a search only enters the cells where all of them are free and inside
+	// of the grid, see Clearance. The clearance of the InvalidNodes and the added obstacles is
+	// precomputed, the blocks of ctx.IsInBlock are checked for the whole footprint at every cell.
+	// The start and the end node are only checked like without it. It needs a bounded grid
+	AgentRadius int
+
+	// MaxOpenNodes bounds the memory of a single search, 0 means no limit.
+	// If the open list holds more than MaxOpenNodes nodes after a node was expanded,
+	// the search is aborted with ErrOpenListOverflow. No open node is pruned,
+	// dropping the nodes with the worst F could drop the only way to the goal
+	// and the search would report ErrorNoPath for a reachable goal.
+	// FindPathJPS, FindPathTheta and FindPathBidirectional keep their own open lists and ignore it
+	MaxOpenNodes int
+
+	// TurnPenalty is added to G for every step which changes the direction of the step before,
+	// so of equally long paths the straighter ones are preferred instead of zigzags.
+	// The search keeps one state per cell, the first cheapest arrival at a cell fixes
+	// the heading of the following steps, so the number of turns is reduced but not
+	// guaranteed to be minimal
+	TurnPenalty int
+
+	// CellWidth and CellHeight are the size of a cell for tiles which are not square,
+	// e.g. 1 and 2 for tiles twice as tall as wide. A step along X costs OrthogonalCost times CellWidth,
+	// along Y times CellHeight and a diagonal step DiagonalCost times the cell diagonal
+	// over sqrt(2), all rounded to integers, so pick an OrthogonalCost of e.g. 10 for fine ratios.
+	// The default heuristics measure the scaled distance, a custom Heuristic is scaled by OrthogonalCost.
+	// 0 means 1, they are not supported on GridHex
+	CellWidth, CellHeight float64
 }
 
 // IContext 提供一些寻路的信息
@@ -34,91 +368,472 @@ type IContext interface {
 	IsNearEnough(x, y int) bool
 }
 
+// ITimedContext is an IContext with blocks which hold for a part of the search only,
+// e.g. fire which clears after some turns. If the ctx of a search implements it,
+// a neighbor is not opened while IsInBlockAt reports its cell as blocked at the time t
+// the path arrives there. t is the G of the arrival, the number of steps with the default
+// cost of 1 per orthogonal step
+//
+// It is a basic time model: a path does not wait for a block to clear and every cell
+// is expanded once at its cheapest free arrival, so a later arrival which would pass a block
+// further on is not tried. FindPathJPS, FindPathTheta and FindPathBidirectional fall back to FindPath,
+// PathFinderF passes the float G rounded down
+type ITimedContext interface {
+	IContext
+	IsInBlockAt(x, y, t int) bool
+}
+
+// IBoundsContext is an IContext which knows the bounds of the world,
+// used by the searches of a PathFinder with Config.Unbounded
+type IBoundsContext interface {
+	IContext
+	IsInBounds(x, y int) bool
+}
+
+// isTimed checks if ctx has blocks which depend on the arrival time
+func isTimed(ctx IContext) bool {
+	_, ok := ctx.(ITimedContext)
+	return ok
+}
+
 type FnIsBlock func(x, y int) bool
 type FnIsReachTar func(x, y int) bool
 
+// PathFinder searches paths on the grid of its Config
+//
+// A PathFinder is safe for concurrent use by multiple goroutines,
+// the searches do not change the config and every search keeps its
+// open and closed list in its own state. The search states and the node records
+// of their parent chains are pooled and reused by later searches,
+// a returned path never references them. AddObstacle and RemoveObstacle
+// may be called at any time, they wait for the running searches. The IContext passed to a search
+// must be safe for concurrent use itself if it is shared between goroutines
 type PathFinder struct {
-	config               Config
-	openList, closedList List
-	startNode, endNode   Node
-	steps                int // 评估的步数
+	lastSteps    int64 // 最近一次搜索展开的节点数, 放在开头保证64位对齐
+	config       Config
+	invalidList  NodeSet             // 静态阻挡, 不随寻路清除
+	blockedEdges map[[4]int]struct{} // 阻挡的边, 只在init中修改
+	weights      map[[2]int]int      // 每个格子WeightedNodes的权重之和, 只在init中修改
+	obstacleMu   sync.RWMutex        // 寻路中持有读锁, 修改invalidList时持有写锁
+	searchPool   sync.Pool           // 复用search, 保留open和closed list的空间
+	regionMu     sync.Mutex          // 保护regions的延迟计算
+	regions      []int               // 每个格子所在连通区域的id, 阻挡变化时置为nil
+	regionCount  int                 // 连通区域的数量
+	clearanceMu  sync.Mutex          // 保护clearance的延迟计算
+	clearance    []int               // 每个格子到最近阻挡的距离, 阻挡变化时置为nil
+	cellCosts    *cellCosts          // 非正方形格子的步长代价, 未设置CellWidth和CellHeight时为nil
+}
+
+// search holds the state of a single FindPath call
+type search struct {
+	finder             *PathFinder
+	openList           openSet // heap, buckets或queue
+	heap               Heap
+	buckets            bucketQueue
+	queue              queueSet // Config.OpenListFactory创建的open list
+	closedList         NodeSet
+	startNode, endNode Node
+	goalList           NodeSet // 多目标寻路时的目标点
+	goalNodes          []Node
+	steps              int     // 评估的步数
+	maxOpen            int     // openList的最大长度
+	flood              bool    // 不估算到终点的H
+	backward           bool    // 反向计算G
+	greedy             bool    // F = H
+	weight             float64 // H的权重
+	openEnd            *Node   // 允许进入的阻挡终点
+	directed           bool    // 到达终点的方向不是approach时加代价
+	approach           [2]int  // 到达终点的最后一步
+	headed             bool    // 第一步与heading不同时加TurnPenalty
+	heading            [2]int  // 进入起点的一步
+	trackBest          bool    // 记录最接近目标的节点
+	bestNode           Node    // 最接近目标的已展开节点
+	bestDist           int     // bestNode到目标的距离
+	neighbors          []Node  // GetNeighborNodes的缓冲区
+	parents            nodePool
+	penalties          map[[2]int]int      // 进入格子的额外代价
+	goalH              func(node Node) int // 到endNode的H, 可以缓存
+}
+
+// SearchStats describes the work done by a single search
+type SearchStats struct {
+	// Expanded is the number of nodes moved to the closedList
+	Expanded int
+	// MaxOpen is the peak number of nodes in the open list
+	MaxOpen int
+	// PathLength is the number of nodes of the returned path, 0 if none was found
+	PathLength int
 }
 
 // New creates a new PathFinder instance
+// it fails on every problem Config.Validate reports, the settings as well as the map data
 func New(config Config) (*PathFinder, error) {
-	if config.GridWidth < 2 || config.GridHeight < 2 {
-		return nil, errors.New("GridWidth and GridHeight must be min 2")
+	a := &PathFinder{}
+	if err := a.Reconfigure(config); err != nil {
+		return nil, err
 	}
-	a := &PathFinder{config: config}
-	return a.init(), nil
+	return a, nil
+}
+
+// Reconfigure replaces the config of the PathFinder, e.g. for a new level
+// the invalid nodes are seeded again and the storage of earlier searches is kept
+//
+// It must not be called while searches of the PathFinder are running,
+// if the config is invalid it returns an error and the old config stays in place
+func (a *PathFinder) Reconfigure(config Config) error {
+	if err := validationError(config.Validate()); err != nil {
+		return err
+	}
+	a.obstacleMu.Lock()
+	defer a.obstacleMu.Unlock()
+
+	a.config = config
+	a.invalidList.Clear()
+	a.invalidateRegions()
+	a.invalidateClearance()
+	a.init()
+	return nil
 }
 
 // init initialised needed properties
 // internal function
 func (a *PathFinder) init() *PathFinder {
-	// add invalidNodes directly to the closedList
-	a.closedList.Add(a.config.InvalidNodes...)
+	// invalidNodes are kept apart from the closedList
+	// so clearing the closedList after a search does not drop them
+	a.invalidList.Add(a.config.InvalidNodes...)
+
+	a.blockedEdges = nil
+	if len(a.config.BlockedEdges) > 0 {
+		a.blockedEdges = make(map[[4]int]struct{}, len(a.config.BlockedEdges))
+		for _, edge := range a.config.BlockedEdges {
+			a.blockedEdges[edgeKey(edge.From, edge.To)] = struct{}{}
+		}
+	}
+
+	// index the weights, enterCost is called for every opened neighbor
+	a.weights = nil
+	if len(a.config.WeightedNodes) > 0 {
+		a.weights = make(map[[2]int]int, len(a.config.WeightedNodes))
+		for _, wNode := range a.config.WeightedNodes {
+			a.weights[nodeKey(wNode)] = addCost(a.weights[nodeKey(wNode)], wNode.Weighting)
+		}
+	}
+
+	// keep an own copy, the caller may reuse its rectangle
+	if a.config.SearchBounds != nil {
+		bounds := *a.config.SearchBounds
+		a.config.SearchBounds = &bounds
+	}
+
+	// every hex neighbor has the same distance
+	if a.config.GridType == GridHex {
+		a.config.AllowDiagonal = false
+		a.config.DisallowCornerCutting = false
+		a.config.DiagonalPolicy = DiagonalAlways
+	}
+	// both settings describe the same rule, the older bool is kept in sync
+	if a.config.DisallowCornerCutting {
+		a.config.DiagonalPolicy = DiagonalOnlyWhenNoObstacles
+	}
+	if a.config.DiagonalPolicy == DiagonalOnlyWhenNoObstacles {
+		a.config.DisallowCornerCutting = true
+	}
+
+	if a.config.OrthogonalCost == 0 {
+		a.config.OrthogonalCost = 1
+		if a.config.AllowDiagonal {
+			a.config.OrthogonalCost = costStraight
+		}
+	}
+	if a.config.DiagonalCost == 0 {
+		a.config.DiagonalCost = costDiagonal
+	}
+
+	a.initCellCosts()
+
+	if a.config.HeuristicWeight == 0 {
+		a.config.HeuristicWeight = 1
+	} else if a.config.HeuristicWeight < 0 {
+		a.config.HeuristicWeight = 0
+	}
 	return a
 }
 
-// H caluclates the absolute distance between
-// nodeA and nodeB calculates by the manhattan distance
+// H caluclates the estimated distance between nodeA and nodeB
+// with the configured heuristic, by default the manhattan distance
+// or the Chebyshev distance with AllowDiagonal
 func (a *PathFinder) H(nodeA Node, nodeB Node) int {
-	absX := math.Abs(float64(nodeA.X - nodeB.X))
-	absY := math.Abs(float64(nodeA.Y - nodeB.Y))
-	return int(absX + absY)
+	if a.config.WrapEdges {
+		return a.wrappedDistance(nodeA, nodeB, a.h)
+	}
+	return a.h(nodeA, nodeB)
+}
+
+// h is H without the wrapped borders
+func (a *PathFinder) h(nodeA Node, nodeB Node) int {
+	if a.config.Heuristic != nil {
+		return a.config.Heuristic(nodeA, nodeB)
+	}
+	return a.moves(nodeA, nodeB)
+}
+
+// moves returns the smallest number of steps between two nodes, the default heuristic
+func (a *PathFinder) moves(nodeA Node, nodeB Node) int {
+	if a.config.GridType == GridHex {
+		return HexDistance(nodeA, nodeB)
+	}
+	if a.config.AllowDiagonal {
+		// manhattan overestimates diagonal steps, the path would not be optimal
+		return ChebyshevDistance(nodeA, nodeB)
+	}
+	return ManhattanDistance(nodeA, nodeB)
 }
 
 // GetNeighborNodes calculates the next neighbors of the given node
 // if a neighbor node is not accessible the node will be ignored
 func (a *PathFinder) GetNeighborNodes(ctx IContext, node Node) []Node {
-	var neighborNodes []Node
+	return a.AppendNeighborNodes(ctx, make([]Node, 0, len(orthogonalOffsets)+len(diagonalOffsets)), node)
+}
+
+// AppendNeighborNodes appends the neighbors of GetNeighborNodes to dst and returns the extended slice,
+// an own search can pass dst[:0] of the last call to reuse the buffer
+func (a *PathFinder) AppendNeighborNodes(ctx IContext, dst []Node, node Node) []Node {
+	a.obstacleMu.RLock()
+	defer a.obstacleMu.RUnlock()
+	return a.appendNeighborNodes(ctx, dst, &node, nil)
+}
+
+// AddObstacle marks the cell as not accessible for the following searches
+// searches which are running keep the obstacles they started with,
+// AddObstacle waits until they are done
+func (a *PathFinder) AddObstacle(x, y int) {
+	a.obstacleMu.Lock()
+	a.invalidList.Add(Node{X: x, Y: y})
+	a.invalidateRegions()
+	a.invalidateClearance()
+	a.obstacleMu.Unlock()
+}
+
+// RemoveObstacle makes the cell accessible again for the following searches
+// it works for the configured InvalidNodes as well as for added obstacles
+func (a *PathFinder) RemoveObstacle(x, y int) {
+	a.obstacleMu.Lock()
+	a.invalidList.Remove(Node{X: x, Y: y})
+	a.invalidateRegions()
+	a.invalidateClearance()
+	a.obstacleMu.Unlock()
+}
 
-	upNode := Node{X: node.X, Y: node.Y + 1, parent: &node}
-	if a.isAccessible(ctx, upNode) {
-		neighborNodes = append(neighborNodes, upNode)
+// appendNeighborNodes appends the neighbors of the given node to neighborNodes
+// so a search can reuse its buffer, node becomes the parent of the neighbors.
+// openEnd is accepted even if it is blocked, nil if there is none
+func (a *PathFinder) appendNeighborNodes(ctx IContext, neighborNodes []Node, node *Node, openEnd *Node) []Node {
+	isAccessible := func(neighbor *Node) bool {
+		if a.isEdgeBlocked(*node, *neighbor) {
+			return false
+		}
+		if a.config.WrapEdges {
+			neighbor.X, neighbor.Y = a.wrapXY(neighbor.X, neighbor.Y)
+		}
+		if openEnd != nil && neighbor.X == openEnd.X && neighbor.Y == openEnd.Y {
+			return a.inWorld(ctx, neighbor.X, neighbor.Y) && a.inSearchBounds(neighbor.X, neighbor.Y)
+		}
+		return a.isAccessible(ctx, *neighbor)
 	}
 
-	downNode := Node{X: node.X, Y: node.Y - 1, parent: &node}
-	if a.isAccessible(ctx, downNode) {
-		neighborNodes = append(neighborNodes, downNode)
+	if a.config.Neighbors != nil {
+		for _, neighbor := range a.config.Neighbors(ctx, *node) {
+			if isAccessible(&neighbor) {
+				neighborNodes = append(neighborNodes, Node{X: neighbor.X, Y: neighbor.Y, Weighting: neighbor.Weighting, parent: node})
+			}
+		}
+		return neighborNodes
 	}
 
-	leftNode := Node{X: node.X - 1, Y: node.Y, parent: &node}
-	if a.isAccessible(ctx, leftNode) {
-		neighborNodes = append(neighborNodes, leftNode)
+	if a.config.GridType == GridHex {
+		for _, offset := range hexOffsets {
+			hexNode := Node{X: node.X + offset[0], Y: node.Y + offset[1], parent: node}
+			if isAccessible(&hexNode) {
+				neighborNodes = append(neighborNodes, hexNode)
+			}
+		}
+		return neighborNodes
 	}
 
-	rightNode := Node{X: node.X + 1, Y: node.Y, parent: &node}
-	if a.isAccessible(ctx, rightNode) {
-		neighborNodes = append(neighborNodes, rightNode)
+	for _, offset := range orthogonalOffsets {
+		orthogonalNode := Node{X: node.X + offset[0], Y: node.Y + offset[1], parent: node}
+		if isAccessible(&orthogonalNode) {
+			neighborNodes = append(neighborNodes, orthogonalNode)
+		}
+	}
+
+	if a.config.AllowDiagonal {
+		for _, offset := range diagonalOffsets {
+			diagonalNode := Node{X: node.X + offset[0], Y: node.Y + offset[1], parent: node}
+			if a.config.DiagonalPolicy != DiagonalAlways && a.isDiagonalRejected(ctx, *node, offset) {
+				continue
+			}
+			if isAccessible(&diagonalNode) {
+				neighborNodes = append(neighborNodes, diagonalNode)
+			}
+		}
 	}
 
 	return neighborNodes
 }
 
+// CanEnter checks if a search may step onto the cell, e.g. to validate the moves of a player
+// with the same rules as the pathfinder. The cell must be
+//   - inside the grid, with WrapEdges the coordinates are wrapped onto it first
+//   - inside the Config.SearchBounds if they are set
+//   - not an InvalidNode, an added obstacle or blocked by ctx.IsInBlock
+//   - not impassable by a Weighting of WeightImpassable, without MoveCost
+//
+// The closedList and the BlockedEdges belong to a search or a single step and are not checked
+func (a *PathFinder) CanEnter(ctx IContext, x, y int) bool {
+	a.obstacleMu.RLock()
+	defer a.obstacleMu.RUnlock()
+	return a.canEnter(ctx, x, y)
+}
+
+// canEnter is CanEnter for callers which already hold the read lock of obstacleMu
+func (a *PathFinder) canEnter(ctx IContext, x, y int) bool {
+	if a.config.WrapEdges {
+		x, y = a.wrapXY(x, y)
+	}
+	if !a.isWalkable(ctx, x, y) {
+		return false
+	}
+	return a.config.MoveCost != nil || a.enterCost(x, y) < infCost
+}
+
 // isAccessible checks if the node is reachable in the grid
 // and is not in the invalidNodes slice
 func (a *PathFinder) isAccessible(ctx IContext, node Node) bool {
+	return a.isWalkable(ctx, node.X, node.Y)
+}
+
+// isWalkable checks if the cell is inside the grid and the search bounds and not blocked
+func (a *PathFinder) isWalkable(ctx IContext, x, y int) bool {
 
 	// if node is out of bound
-	if node.X < 0 || node.Y < 0 || node.X > a.config.GridWidth-1 || node.Y > a.config.GridHeight-1 {
+	if !a.inWorld(ctx, x, y) || !a.inSearchBounds(x, y) {
 		return false
 	}
 
-	if ctx != nil {
-		if ctx.IsInBlock(node.X, node.Y) {
-			return false
+	if a.isBlocked(ctx, x, y) {
+		return false
+	}
+	return a.config.AgentRadius == 0 || a.hasClearance(ctx, x, y)
+}
+
+// inBounds checks if the cell is inside the grid
+func (a *PathFinder) inBounds(x, y int) bool {
+	gridX, gridY := x-a.config.OriginX, y-a.config.OriginY
+	return gridX >= 0 && gridY >= 0 && gridX < a.config.GridWidth && gridY < a.config.GridHeight
+}
+
+// inWorld checks if a search may visit the cell, a cell of the grid
+// or with Config.Unbounded a cell which the ctx accepts
+func (a *PathFinder) inWorld(ctx IContext, x, y int) bool {
+	if !a.config.Unbounded {
+		return a.inBounds(x, y)
+	}
+	if bounds, ok := ctx.(IBoundsContext); ok {
+		return bounds.IsInBounds(x, y)
+	}
+	return true
+}
+
+// inSearchBounds checks if the cell is inside the Config.SearchBounds,
+// every cell is if they are not set
+func (a *PathFinder) inSearchBounds(x, y int) bool {
+	return a.config.SearchBounds == nil || a.config.SearchBounds.Contains(x, y)
+}
+
+// checkNode returns ErrOutOfBounds or blockedErr if the node cannot be part of a path
+// and ErrorNoPath if it is outside the search bounds,
+// with AllowBlockedEndpoints only the bounds are checked
+func (a *PathFinder) checkNode(ctx IContext, node Node, blockedErr error) error {
+	if !a.inWorld(ctx, node.X, node.Y) {
+		return ErrOutOfBounds
+	}
+	if !a.inSearchBounds(node.X, node.Y) {
+		return ErrorNoPath
+	}
+	if a.isBlocked(ctx, node.X, node.Y) && !a.config.AllowBlockedEndpoints {
+		return blockedErr
+	}
+	return nil
+}
+
+// checkEndpoints validates the start and the end node before a search
+// with allowNear a blocked end node is fine if ctx.IsNearEnough or the GoalRadius accepts it,
+// the path ends next to it then
+func (a *PathFinder) checkEndpoints(ctx IContext, startNode, endNode Node, allowNear bool) error {
+	if err := a.checkNode(ctx, startNode, ErrStartBlocked); err != nil {
+		return err
+	}
+	err := a.checkNode(ctx, endNode, ErrEndBlocked)
+	if err == ErrEndBlocked && allowNear {
+		if ctx != nil && ctx.IsNearEnough(endNode.X, endNode.Y) {
+			return nil
+		}
+		if a.usesGoalRadius(ctx) {
+			return nil
 		}
 	}
+	return err
+}
 
-	// check if the node is in the closedList
-	// the predefined invalidNodes are also in this list
-	if a.closedList.Contains(node) {
-		return false
+// usesGoalRadius checks if a search with ctx stops within the GoalRadius of the end node,
+// the radius only applies to the searches without an IContext
+func (a *PathFinder) usesGoalRadius(ctx IContext) bool {
+	return ctx == nil && a.config.GoalRadius > 0
+}
+
+// gridDistance returns the number of steps between two nodes on an open grid
+func (a *PathFinder) gridDistance(nodeA, nodeB Node) int {
+	if a.config.WrapEdges {
+		return a.wrappedDistance(nodeA, nodeB, a.openGridDistance)
 	}
+	return a.openGridDistance(nodeA, nodeB)
+}
 
-	return true
+// openGridDistance is gridDistance without the wrapped borders
+func (a *PathFinder) openGridDistance(nodeA, nodeB Node) int {
+	switch {
+	case a.config.GridType == GridHex:
+		return HexDistance(nodeA, nodeB)
+	case a.config.AllowDiagonal:
+		return ChebyshevDistance(nodeA, nodeB)
+	default:
+		return ManhattanDistance(nodeA, nodeB)
+	}
+}
+
+// isDiagonalRejected checks if the DiagonalPolicy rejects the diagonal step from node
+// by offset because of the two orthogonal cells beside it
+func (a *PathFinder) isDiagonalRejected(ctx IContext, node Node, offset [2]int) bool {
+	sideX, sideY := node.X+offset[0], node.Y+offset[1]
+	if a.config.WrapEdges {
+		sideX, _ = a.wrapXY(sideX, node.Y)
+		_, sideY = a.wrapXY(node.X, sideY)
+	}
+	blockedX, blockedY := a.isBlocked(ctx, sideX, node.Y), a.isBlocked(ctx, node.X, sideY)
+	if a.config.DiagonalPolicy == DiagonalNoCornerCutting {
+		return blockedX && blockedY
+	}
+	return blockedX || blockedY
+}
+
+// isBlocked checks if the cell is an obstacle,
+// either a configured invalid node or blocked by the context
+func (a *PathFinder) isBlocked(ctx IContext, x, y int) bool {
+	if ctx != nil && ctx.IsInBlock(x, y) {
+		return true
+	}
+	return a.invalidList.Contains(Node{X: x, Y: y})
 }
 
 // IsEndNode checks if the given node has
@@ -128,111 +843,715 @@ func (a *PathFinder) IsEndNode(ctx IContext, checkNode, endNode Node) bool {
 		if ctx.IsNearEnough(checkNode.X, checkNode.Y) {
 			return true
 		}
+	} else if a.config.GoalRadius > 0 && a.gridDistance(checkNode, endNode) <= a.config.GoalRadius {
+		return true
 	}
 	return checkNode.X == endNode.X && checkNode.Y == endNode.Y
 }
 
+// isGoal checks if the search can stop at the given node,
+// either the end node or one of the goal nodes of a multi goal search
+func (s *search) isGoal(ctx IContext, checkNode Node) bool {
+	if s.goalList.IsEmpty() {
+		return s.finder.IsEndNode(ctx, checkNode, s.endNode)
+	}
+	if ctx != nil && ctx.IsNearEnough(checkNode.X, checkNode.Y) {
+		return true
+	}
+	return s.goalList.Contains(checkNode)
+}
+
 // FindPath starts the a* algorithm for the given start and end node
 // The return value will be the fastest way represented as a nodes slice
 //
-// If no path was found it returns nil and an error
-
+// The path is ordered from start to goal, the start node is on index 0
+// and the end node is the last one, so it can be walked front to back.
+// If ctx.IsNearEnough accepts a node first, the path ends at that node instead,
+// FindPathReached tells both cases apart.
+// If the start node is the end node, the path holds only the start node with
+// the cost 0 and the error is nil, there is no move to make
+//
+// If the start or the end node is out of bounds or blocked it returns
+// ErrOutOfBounds, ErrStartBlocked or ErrEndBlocked without searching.
+// If no path was found it returns nil and ErrorNoPath
 func (a *PathFinder) FindPath(ctx IContext, startNode, endNode Node) ([]Node, error) {
-	return a.doFindPath(ctx, startNode, endNode, StepsNoLimit)
+	return a.doFindPath(context.Background(), ctx, startNode, endNode, searchOptions{maxSteps: StepsNoLimit})
 }
 
+// FindPathReached works like FindPath and also reports if the path
+// ends at the exact end node or at a node accepted by ctx.IsNearEnough
+//
+// H is still estimated to the end node, so the search heads for the
+// near enough nodes around it even if the end node itself is blocked
+func (a *PathFinder) FindPathReached(ctx IContext, startNode, endNode Node) (path []Node, reachedExact bool, err error) {
+	path, err = a.doFindPath(context.Background(), ctx, startNode, endNode, searchOptions{maxSteps: StepsNoLimit})
+	if err != nil {
+		return nil, false, err
+	}
+	last := path[len(path)-1]
+	return path, last.X == endNode.X && last.Y == endNode.Y, nil
+}
+
+// FindPathWithCost works like FindPath and also returns the accumulated
+// cost of the path, including weighting and diagonal step costs
+func (a *PathFinder) FindPathWithCost(ctx IContext, startNode, endNode Node) ([]Node, int, error) {
+	foundPath, err := a.doFindPath(context.Background(), ctx, startNode, endNode, searchOptions{maxSteps: StepsNoLimit})
+	if err != nil {
+		return nil, 0, err
+	}
+	// the end node is the last one
+	return foundPath, foundPath[len(foundPath)-1].g, nil
+}
+
+// FindPathEx works like FindPath but stops after maxSteps expanded nodes
+// if the goal was not reached by then it returns ErrStepLimitReached,
+// with PartialPathOnStepLimit set the path to the last expanded node
+// in start to goal order is returned together with the error
 func (a *PathFinder) FindPathEx(ctx IContext, startNode, endNode Node, maxSteps int) ([]Node, error) {
-	return a.doFindPath(ctx, startNode, endNode, maxSteps)
+	return a.doFindPath(context.Background(), ctx, startNode, endNode, searchOptions{maxSteps: maxSteps})
 }
 
-func (a *PathFinder) doFindPath(ctx IContext, startNode, endNode Node, maxSteps int) ([]Node, error) {
+// FindPathFunc works like FindPath but calls shouldContinue after every expanded node
+// with the number of expanded nodes and the F of that node, the smallest F of the open list,
+// e.g. to split a search over several frames by time instead of a fixed number of steps
+//
+// If shouldContinue returns false before the goal is reached the search stops
+// and returns the path to the expanded node closest to the end node together
+// with ErrSearchStopped, the path is ordered from start to that node
+func (a *PathFinder) FindPathFunc(ctx IContext, startNode, endNode Node, shouldContinue func(steps, minF int) bool) ([]Node, error) {
+	return a.doFindPath(context.Background(), ctx, startNode, endNode, searchOptions{maxSteps: StepsNoLimit, shouldContinue: shouldContinue})
+}
 
-	a.startNode = startNode
-	a.endNode = endNode
-	a.steps = 0
+// FindPathWithinCost works like FindPath but only accepts paths
+// whose accumulated cost is not higher than maxCost
+// nodes beyond the budget are not opened, if the goal cannot be reached
+// within maxCost it returns ErrorNoPath
+func (a *PathFinder) FindPathWithinCost(ctx IContext, startNode, endNode Node, maxCost int) ([]Node, error) {
+	if maxCost < 0 {
+		return nil, ErrorNoPath
+	}
+	opts := searchOptions{
+		maxSteps:  StepsNoLimit,
+		costLimit: true,
+		maxCost:   maxCost,
+	}
+	return a.doFindPath(context.Background(), ctx, startNode, endNode, opts)
+}
 
-	defer func() {
-		a.openList.Clear()
-		a.closedList.Clear()
-	}()
+// FindPathGreedy runs a greedy best-first search, the open list is ordered by H alone
+// and the accumulated cost is ignored, F = H
+//
+// It rushes towards the end node and expands far fewer nodes than FindPath,
+// but the path may be much longer than the one of FindPath, e.g. for background units.
+// G is still accumulated, so the nodes of the path hold the cost of the path they found
+func (a *PathFinder) FindPathGreedy(ctx IContext, startNode, endNode Node) ([]Node, error) {
+	return a.doFindPath(context.Background(), ctx, startNode, endNode, searchOptions{maxSteps: StepsNoLimit, greedy: true})
+}
 
-	a.openList.Add(startNode)
+// IsReachable checks if there is a path from the start to the end node
+// it runs the same search as FindPath but does not build the path
+func (a *PathFinder) IsReachable(ctx IContext, startNode, endNode Node) (bool, error) {
+	opts := searchOptions{maxSteps: StepsNoLimit, skipPath: true}
+	_, err := a.doFindPath(context.Background(), ctx, startNode, endNode, opts)
+	if errors.Is(err, ErrorNoPath) {
+		return false, nil
+	}
+	return err == nil, err
+}
 
-	for !a.openList.IsEmpty() {
+// FindPathContext works like FindPath but can be cancelled by cancelCtx
+// the search checks cancelCtx periodically and returns its error once it is done
+func (a *PathFinder) FindPathContext(cancelCtx context.Context, ctx IContext, startNode, endNode Node) ([]Node, error) {
+	return a.doFindPath(cancelCtx, ctx, startNode, endNode, searchOptions{maxSteps: StepsNoLimit})
+}
 
-		currentNode, err := a.openList.GetMinFNode()
-		if err != nil {
-			return nil, fmt.Errorf("cannot get minF node %v", err)
+// FindPathDebug works like FindPath and also returns every node
+// moved to the closedList during the search, in the order of expansion
+// the configured InvalidNodes are not part of explored
+func (a *PathFinder) FindPathDebug(ctx IContext, startNode, endNode Node) (path []Node, explored []Node, err error) {
+	opts := searchOptions{
+		maxSteps: StepsNoLimit,
+		onExpand: func(node Node) {
+			// the parent lives in the nodePool of the search, which is reused
+			node.parent = nil
+			explored = append(explored, node)
+		},
+	}
+	path, err = a.doFindPath(context.Background(), ctx, startNode, endNode, opts)
+	return path, explored, err
+}
+
+// FindPathWithObserver works like FindPath and calls onExpand for every node
+// moved to the closedList, in the order of expansion, e.g. to record the frames of an animation
+//
+// onExpand is called right after the node is closed, before the node is checked
+// for the goal and before its neighbors are generated and opened, so the goal node
+// is the last node passed to it. It gets a copy of the node and cannot change the search.
+// onExpand runs while the search holds the obstacles, it must not call AddObstacle,
+// RemoveObstacle or Reconfigure of the same PathFinder
+func (a *PathFinder) FindPathWithObserver(ctx IContext, startNode, endNode Node, onExpand func(n Node)) ([]Node, error) {
+	opts := searchOptions{maxSteps: StepsNoLimit}
+	if onExpand != nil {
+		opts.onExpand = func(node Node) {
+			node.parent = nil
+			onExpand(node)
 		}
+	}
+	return a.doFindPath(context.Background(), ctx, startNode, endNode, opts)
+}
 
-		a.openList.Remove(currentNode)
-		a.closedList.Add(currentNode)
-		a.steps++
+// FindPathAvoiding works like FindPath but entering a cell costs the extra penalty given for
+// its X/Y coordinates, e.g. to spread a crowd over the cells the earlier units already use.
+// The penalties only apply to this search, unlike the WeightedNodes of the Config.
+// Negative penalties are ignored, the map is only read
+func (a *PathFinder) FindPathAvoiding(ctx IContext, startNode, endNode Node, penalty map[[2]int]int) ([]Node, error) {
+	opts := searchOptions{maxSteps: StepsNoLimit, penalties: penalty}
+	return a.doFindPath(context.Background(), ctx, startNode, endNode, opts)
+}
 
-		// we found the path
-		if a.IsEndNode(ctx, currentNode, endNode) {
-			return a.getNodePath(currentNode), nil
-		}
+// FindPathMulti searches the path from the start node to the nearest of the goal nodes
+// it runs a single search and stops as soon as any goal is reached,
+// the heuristic is the minimal distance to all goals
+func (a *PathFinder) FindPathMulti(ctx IContext, startNode Node, goalNodes []Node) ([]Node, error) {
+	if len(goalNodes) == 0 {
+		return nil, errors.New("no goal nodes")
+	}
+	opts := searchOptions{
+		maxSteps:  StepsNoLimit,
+		goalNodes: goalNodes,
+	}
+	return a.doFindPath(context.Background(), ctx, startNode, goalNodes[0], opts)
+}
 
-		if maxSteps > 0 && a.steps >= maxSteps {
-			// 最大探测节点数
-			// 直接返回当前路径
-			return a.getNodePath(currentNode), nil
+// FindPathMultiSource searches the cheapest path from any of the start nodes to the end node,
+// e.g. for the nearest of several guards. It runs a single search with all start nodes opened
+// at the cost 0 and returns the path and the start node it begins with.
+// Every start node is checked like the one of FindPath
+func (a *PathFinder) FindPathMultiSource(ctx IContext, startNodes []Node, endNode Node) ([]Node, Node, error) {
+	if len(startNodes) == 0 {
+		return nil, Node{}, errors.New("no start nodes")
+	}
+	opts := searchOptions{
+		maxSteps:   StepsNoLimit,
+		startNodes: startNodes,
+	}
+	foundPath, err := a.doFindPath(context.Background(), ctx, startNodes[0], endNode, opts)
+	if err != nil {
+		return nil, Node{}, err
+	}
+	for _, startNode := range startNodes {
+		if startNode.X == foundPath[0].X && startNode.Y == foundPath[0].Y {
+			return foundPath, startNode, nil
 		}
+	}
+	return foundPath, foundPath[0], nil
+}
 
-		neighbors := a.GetNeighborNodes(ctx, currentNode)
-		for _, neighbor := range neighbors {
-			if a.closedList.Contains(neighbor) {
-				continue
-			}
+// Steps returns the number of nodes the last search of FindPath or its variants moved to the
+// closedList, on success as well as on failure. It is overwritten by the next search,
+// with concurrent searches it is the count of the one which finished last.
+// A Stepper sets it after its last Step.
+// FindPathJPS, FindPathTheta and FindPathBidirectional do not set it, use FindPathStats
+// to get the count of one search for sure
+func (a *PathFinder) Steps() int {
+	return int(atomic.LoadInt64(&a.lastSteps))
+}
+
+// FindPathStats works like FindPath and also returns the statistics of the search,
+// e.g. to profile a grid or to tune the maxSteps of FindPathEx
+// the stats are returned per call so concurrent searches do not mix them up
+func (a *PathFinder) FindPathStats(ctx IContext, startNode, endNode Node) ([]Node, SearchStats, error) {
+	var stats SearchStats
+	opts := searchOptions{maxSteps: StepsNoLimit, stats: &stats}
+	foundPath, err := a.doFindPath(context.Background(), ctx, startNode, endNode, opts)
+	stats.PathLength = len(foundPath)
+	return foundPath, stats, err
+}
+
+// searchOptions holds the optional settings of a single search
+type searchOptions struct {
+	maxSteps   int
+	onExpand   func(node Node) // 节点进入closedList时回调
+	goalNodes  []Node          // 设置后替代endNode
+	startNodes []Node          // 设置后替代startNode, 所有起点的G都为0
+	stats      *SearchStats    // 设置后记录搜索统计
+	costLimit  bool            // 限制路径代价不超过maxCost
+	maxCost    int
+	skipPath   bool    // 到达目标时不生成路径
+	flood      bool    // 没有目标, H为0或goalH, 展开所有可达节点
+	backward   bool    // G为从节点走到起点的代价
+	greedy     bool    // F只用H排序
+	weight     float64 // 大于0时替代HeuristicWeight
+	directed   bool    // 最后一步的方向不是approach时加上approachPenalty
+	approach   [2]int
+	penalties  map[[2]int]int // 本次寻路进入格子的额外代价
+	headed     bool           // 起点有进入方向heading, 第一步转向时加TurnPenalty
+	heading    [2]int
+
+	goalH          func(node Node) int        // 设置后替代到endNode的hCost
+	shouldContinue func(steps, minF int) bool // 返回false时停止并返回最接近目标的路径
+	skipStep       func(from, to Node) bool   // 返回true时不打开to
+}
+
+func (a *PathFinder) doFindPath(cancelCtx context.Context, ctx IContext, startNode, endNode Node, opts searchOptions) ([]Node, error) {
+	a.obstacleMu.RLock()
+	defer a.obstacleMu.RUnlock()
+	return a.findPathLocked(cancelCtx, ctx, startNode, endNode, opts)
+}
+
+// findPathLocked is doFindPath for callers which already hold the read lock of obstacleMu
+func (a *PathFinder) findPathLocked(cancelCtx context.Context, ctx IContext, startNode, endNode Node, opts searchOptions) ([]Node, error) {
+	if !opts.flood && len(opts.goalNodes) == 0 {
+		endNode = a.snapEnd(ctx, endNode)
+	}
+	if err := a.checkSearch(ctx, startNode, endNode, opts); err != nil {
+		return nil, err
+	}
+
+	// only allocated with the check, the searches without it stay free of the closure
+	var expanded *[]Node
+	if a.config.CheckAdmissibility && !opts.flood && len(opts.goalNodes) == 0 {
+		expanded = new([]Node)
+		opts.onExpand = recordExpanded(expanded, opts.onExpand)
+	}
+
+	s := a.getSearch()
+	defer a.putSearch(s)
+
+	a.startSearch(s, startNode, endNode, opts)
+	foundPath, err := s.run(cancelCtx, ctx, opts)
+	if opts.stats != nil {
+		opts.stats.Expanded = s.steps
+		opts.stats.MaxOpen = s.maxOpen
+	}
+	if err == nil && expanded != nil && len(foundPath) > 0 {
+		err = a.checkAdmissibility(cancelCtx, ctx, *expanded, foundPath[len(foundPath)-1], endNode)
+	}
+	// stored after the flood of the check, which is no search of the caller
+	atomic.StoreInt64(&a.lastSteps, int64(s.steps))
+	return foundPath, err
+}
 
-			a.calculateNode(&neighbor)
+// snapEnd returns the NearestWalkable cell of an end node which cannot be entered
+// if Config.SnapBlockedEnd is set, otherwise the end node. The caller holds the read lock of obstacleMu
+func (a *PathFinder) snapEnd(ctx IContext, endNode Node) Node {
+	if !a.config.SnapBlockedEnd || a.canEnter(ctx, endNode.X, endNode.Y) {
+		return endNode
+	}
+	if nearestNode, ok := a.nearestWalkable(ctx, endNode.X, endNode.Y); ok {
+		return nearestNode
+	}
+	return endNode
+}
 
-			if !a.openList.Contains(neighbor) {
-				a.openList.Add(neighbor)
+// checkSearch validates the endpoints of a search before it starts
+func (a *PathFinder) checkSearch(ctx IContext, startNode, endNode Node, opts searchOptions) error {
+	// the goals of a multi goal search and a flood are not validated
+	if opts.flood || len(opts.goalNodes) > 0 {
+		return a.checkNode(ctx, startNode, ErrStartBlocked)
+	}
+	// the regions are not compared, a single search answers it for all start nodes
+	if len(opts.startNodes) > 0 {
+		for _, node := range opts.startNodes {
+			if err := a.checkEndpoints(ctx, node, endNode, true); err != nil {
+				return err
 			}
 		}
+		return nil
+	}
+	if err := a.checkEndpoints(ctx, startNode, endNode, true); err != nil {
+		return err
+	}
+	if a.config.RegionCheck && ctx == nil && a.config.GoalRadius == 0 && !a.config.AllowBlockedEndpoints &&
+		!a.sameRegion(startNode, endNode) {
+		return ErrorNoPath
+	}
+	return nil
+}
 
+// startSearch prepares the search state for a search from startNode to endNode
+// and opens the start node
+func (a *PathFinder) startSearch(s *search, startNode, endNode Node, opts searchOptions) {
+	s.heap.items.tieBreaking = a.config.TieBreaking
+	s.buckets.overflow.items.tieBreaking = a.config.TieBreaking
+	s.openList = &s.heap
+	if a.config.BucketOpenList {
+		s.openList = &s.buckets
+	}
+	if a.config.OpenListFactory != nil {
+		s.queue.queue = a.config.OpenListFactory()
+		s.openList = &s.queue
 	}
+	s.startNode = startNode
+	s.endNode = endNode
+	s.goalNodes = opts.goalNodes
+	s.goalList.Add(opts.goalNodes...)
+	s.flood = opts.flood
+	s.backward = opts.backward
+	s.greedy = opts.greedy
+	s.weight = a.config.HeuristicWeight
+	if opts.weight > 0 {
+		s.weight = opts.weight
+	}
+	if a.config.AllowBlockedEndpoints && !opts.flood && len(opts.goalNodes) == 0 {
+		s.openEnd = &s.endNode
+	}
+	s.directed, s.approach = opts.directed, opts.approach
+	s.headed, s.heading = opts.headed, opts.heading
+	s.penalties = opts.penalties
+	s.goalH = opts.goalH
+	s.openList.push(s.startNode)
+	for _, node := range opts.startNodes {
+		node.parent, node.g = nil, 0
+		if _, ok := s.openList.get(node); !ok {
+			s.openList.push(node)
+		}
+	}
+	s.maxOpen = s.openList.size()
+	s.trackBest = opts.shouldContinue != nil || (a.config.NoPathDetails && !opts.flood)
+}
 
-	return nil, ErrorNoPath
+// getSearch returns an unused search state, the storage of earlier searches is reused
+func (a *PathFinder) getSearch() *search {
+	if s, ok := a.searchPool.Get().(*search); ok {
+		return s
+	}
+	return &search{finder: a}
+}
+
+// putSearch clears the search state and keeps it for the next search
+func (a *PathFinder) putSearch(s *search) {
+	s.heap.Clear()
+	s.buckets.clear()
+	s.queue.clear()
+	s.closedList.Clear()
+	s.goalList.Clear()
+	s.goalNodes = nil
+	s.parents.reset()
+	s.steps = 0
+	s.maxOpen = 0
+	s.flood = false
+	s.backward = false
+	s.greedy = false
+	s.openEnd = nil
+	s.directed = false
+	s.penalties = nil
+	s.goalH = nil
+	s.trackBest = false
+	s.bestNode, s.bestDist = Node{}, 0
+	a.searchPool.Put(s)
+}
+
+// run expands the nodes of the open list until a goal is reached
+func (s *search) run(cancelCtx context.Context, ctx IContext, opts searchOptions) ([]Node, error) {
+	for {
+		if done, foundPath, err := s.step(cancelCtx, ctx, opts); done {
+			return foundPath, err
+		}
+	}
+}
+
+// step expands the node with the smallest F of the open list
+// done reports that the search is over, then the path and the error are the result of the search
+func (s *search) step(cancelCtx context.Context, ctx IContext, opts searchOptions) (done bool, foundPath []Node, err error) {
+	a := s.finder
+	if s.openList.size() == 0 {
+		if a.config.NoPathDetails && !opts.flood {
+			return true, nil, &NoPathError{ExpandedCount: s.steps, ClosestNode: Node{X: s.bestNode.X, Y: s.bestNode.Y}}
+		}
+		return true, nil, ErrorNoPath
+	}
+
+	currentNode, err := s.openList.popMin()
+	if err != nil {
+		return true, nil, fmt.Errorf("cannot get minF node %w", err)
+	}
+
+	s.closedList.Add(currentNode)
+	s.steps++
+	if opts.onExpand != nil {
+		opts.onExpand(currentNode)
+	}
+
+	if s.steps%cancelCheckSteps == 0 {
+		if err := cancelCtx.Err(); err != nil {
+			return true, nil, err
+		}
+	}
+
+	// we found the path
+	if !opts.flood && s.isGoal(ctx, currentNode) {
+		if opts.skipPath {
+			return true, nil, nil
+		}
+		return true, a.getNodePath(currentNode), nil
+	}
+
+	if s.trackBest {
+		// H may be disabled or weighted, so the distance is measured again
+		if dist := s.goalDistance(currentNode); s.steps == 1 || dist < s.bestDist {
+			s.bestNode, s.bestDist = currentNode, dist
+		}
+	}
+	if opts.shouldContinue != nil {
+		if !opts.shouldContinue(s.steps, currentNode.f) {
+			return true, a.getNodePath(s.bestNode), ErrSearchStopped
+		}
+	}
+
+	if opts.maxSteps > 0 && s.steps >= opts.maxSteps {
+		// 最大探测节点数
+		// 按配置返回当前路径
+		if a.config.PartialPathOnStepLimit {
+			return true, a.getNodePath(currentNode), ErrStepLimitReached
+		}
+		return true, nil, ErrStepLimitReached
+	}
+
+	timed, _ := ctx.(ITimedContext)
+	s.neighbors = a.appendNeighborNodes(ctx, s.neighbors[:0], s.parents.alloc(currentNode), s.openEnd)
+	for _, neighbor := range s.neighbors {
+		if s.closedList.Contains(neighbor) {
+			continue
+		}
+		if opts.skipStep != nil && opts.skipStep(currentNode, neighbor) {
+			continue
+		}
+
+		s.calculateNode(&neighbor)
+		// the cost saturated, the node is impassable
+		if neighbor.g >= infCost {
+			continue
+		}
+		if opts.costLimit && neighbor.g > opts.maxCost {
+			continue
+		}
+		// the G of a backward search is the time to the goal, not since the start
+		if timed != nil && !s.backward && timed.IsInBlockAt(neighbor.X, neighbor.Y, neighbor.g) {
+			continue
+		}
+
+		// relax the open node if the route through currentNode is cheaper
+		openNode, ok := s.openList.get(neighbor)
+		if !ok {
+			s.openList.push(neighbor)
+		} else if neighbor.g < openNode.g {
+			s.openList.update(neighbor)
+		}
+	}
+	if s.openList.size() > s.maxOpen {
+		s.maxOpen = s.openList.size()
+	}
+	if a.config.MaxOpenNodes > 0 && s.openList.size() > a.config.MaxOpenNodes {
+		return true, nil, ErrOpenListOverflow
+	}
+	return false, nil, nil
+}
+
+// turns checks if the step into the node changes the direction of the step into its parent
+func (s *search) turns(node Node) bool {
+	a := s.finder
+	if node.parent.parent != nil {
+		return a.stepDelta(*node.parent.parent, *node.parent) != a.stepDelta(*node.parent, node)
+	}
+	// the parent of the start node is nil, the first step only turns from a given heading
+	return s.headed && s.heading != a.stepDelta(*node.parent, node)
 }
 
 // calculateNode calculates the F, G and H value for the given node
-func (a *PathFinder) calculateNode(node *Node) {
+// G is accumulated from the parent node plus the cost of the step
+func (s *search) calculateNode(node *Node) {
+	a := s.finder
 
-	node.g++
+	if s.backward {
+		// the step leads from the node to its parent
+		node.g = addCost(node.parent.g, a.stepCost(*node, *node.parent))
+	} else {
+		node.g = addCost(node.parent.g, a.stepCost(*node.parent, *node))
+	}
+	if s.penalties != nil {
+		// negative penalties would make H overestimate
+		if penalty := s.penalties[nodeKey(*node)]; penalty > 0 {
+			node.g = addCost(node.g, penalty)
+		}
+	}
+	if a.config.TurnPenalty > 0 && s.turns(*node) {
+		node.g = addCost(node.g, a.config.TurnPenalty)
+	}
+	if s.directed && node.X == s.endNode.X && node.Y == s.endNode.Y && a.stepDelta(*node.parent, *node) != s.approach {
+		node.g = addCost(node.g, approachPenalty)
+	}
+
+	node.h = s.estimateCost(*node)
+	if s.greedy {
+		node.f = node.h
+	} else {
+		node.f = addCost(node.g, scaleH(s.weight, node.h))
+	}
+
+	if a.config.TieBreaking == TieBreakCrossProduct {
+		node.tie = crossProduct(*node, s.startNode, s.endNode)
+	}
+}
+
+// crossProduct returns how far the node deviates from
+// the straight line between the start and the end node
+func crossProduct(node, startNode, endNode Node) int {
+	dx1 := node.X - endNode.X
+	dy1 := node.Y - endNode.Y
+	dx2 := startNode.X - endNode.X
+	dy2 := startNode.Y - endNode.Y
+	cross := dx1*dy2 - dx2*dy1
+	if cross < 0 {
+		return -cross
+	}
+	return cross
+}
+
+// weightH scales h with the configured HeuristicWeight
+func (a *PathFinder) weightH(h int) int {
+	return scaleH(a.config.HeuristicWeight, h)
+}
+
+// scaleH returns weight * h rounded to the next integer
+func scaleH(weight float64, h int) int {
+	if weight == 1 {
+		return h
+	}
+	return int(math.Round(weight * float64(h)))
+}
+
+// stepCost returns the cost of moving from a node to its neighbor
+// including the cost of entering the neighbor and its own Weighting
+func (a *PathFinder) stepCost(from, to Node) int {
+	if a.config.MoveCost != nil {
+		cost := a.config.MoveCost(from, to)
+		if cost < 0 {
+			cost = 0
+		}
+		return addCost(cost, to.Weighting)
+	}
+	return addCost(addCost(a.moveCost(from, to), a.enterCost(to.X, to.Y)), to.Weighting)
+}
+
+// addCost adds two costs, the sum saturates at infCost instead of overflowing
+func addCost(a, b int) int {
+	if a >= infCost || b >= infCost || (b > 0 && a > infCost-b) {
+		return infCost
+	}
+	return a + b
+}
+
+// enterCost returns the extra cost of entering the cell
+// from the CostFunc if set, otherwise from the WeightedNodes and the WeightedRegions
+func (a *PathFinder) enterCost(x, y int) int {
+	if a.config.CostFunc != nil {
+		return a.config.CostFunc(x, y)
+	}
 
 	// check for special node weighting
-	for _, wNode := range a.config.WeightedNodes {
-		if node.X == wNode.X && node.Y == wNode.Y {
-			node.g = node.g + wNode.Weighting
+	cost := a.weights[[2]int{x, y}]
+	for _, region := range a.config.WeightedRegions {
+		if region.Bounds.Contains(x, y) {
+			cost = addCost(cost, region.Weighting)
+		}
+	}
+	return cost
+}
+
+// estimateCost returns the heuristic cost from node to the end node
+func (s *search) estimateCost(node Node) int {
+	a := s.finder
+	// a flood has no goal, only a potential like the one of FindFleePath
+	if a.config.DisableHeuristic || (s.flood && s.goalH == nil) {
+		return 0
+	}
+	if len(s.goalNodes) == 0 {
+		if s.goalH != nil {
+			return s.goalH(node)
 		}
+		return a.hCost(node, s.endNode)
 	}
 
-	node.h = a.H(*node, a.endNode)
-	node.f = node.g + node.h
+	minCost := -1
+	for _, goalNode := range s.goalNodes {
+		if cost := a.hCost(node, goalNode); minCost < 0 || cost < minCost {
+			minCost = cost
+		}
+	}
+	return minCost
 }
 
-// getNodePath returns the chain of parent nodes
-// the given node will be still included in the nodes slice
-func (a *PathFinder) getNodePath(currentNode Node) []Node {
-	var nodePath []Node
-	nodePath = append(nodePath, currentNode)
-	for {
-		if currentNode.parent == nil {
-			break
+// hCost returns the heuristic cost between two nodes in the units of the step costs
+func (a *PathFinder) hCost(nodeA, nodeB Node) int {
+	if a.cellCosts != nil && a.config.Heuristic == nil {
+		if a.config.WrapEdges {
+			return a.moveFloor(nodeA, nodeB, a.wrappedDistance(nodeA, nodeB, a.cellDistance))
 		}
+		return a.moveFloor(nodeA, nodeB, a.cellDistance(nodeA, nodeB))
+	}
+	return a.moveFloor(nodeA, nodeB, a.H(nodeA, nodeB)*a.straightCost())
+}
 
-		parentNode := *currentNode.parent
+// moveFloor adds the extra of MinMoveCost for every step between the nodes to the cost
+func (a *PathFinder) moveFloor(nodeA, nodeB Node, cost int) int {
+	extra := a.config.MinMoveCost - a.straightCost()
+	if extra <= 0 {
+		return cost
+	}
+	moves := a.moves(nodeA, nodeB)
+	if a.config.WrapEdges {
+		moves = a.wrappedDistance(nodeA, nodeB, a.moves)
+	}
+	return addCost(cost, moves*extra)
+}
+
+// goalDistance returns the unscaled heuristic distance from node to the nearest goal
+func (s *search) goalDistance(node Node) int {
+	a := s.finder
+	if len(s.goalNodes) == 0 {
+		return a.H(node, s.endNode)
+	}
 
-		// if the end of node chain
-		if parentNode.parent == nil {
-			break
+	minH := -1
+	for _, goalNode := range s.goalNodes {
+		if h := a.H(node, goalNode); minH < 0 || h < minH {
+			minH = h
 		}
+	}
+	return minH
+}
+
+// straightCost returns the cost of one orthogonal step
+func (a *PathFinder) straightCost() int {
+	return a.config.OrthogonalCost
+}
+
+// moveCost returns the cost of a single step from one node to its neighbor
+// without diagonal movement every step is an orthogonal one
+func (a *PathFinder) moveCost(from, to Node) int {
+	if a.cellCosts != nil {
+		return a.cellCosts.moveCost(from, to)
+	}
+	if a.config.AllowDiagonal && from.X != to.X && from.Y != to.Y {
+		return a.config.DiagonalCost
+	}
+	return a.config.OrthogonalCost
+}
+
+// getNodePath returns the chain of parent nodes in start to goal order
+// the given node and the start node are included in the nodes slice
+func (a *PathFinder) getNodePath(currentNode Node) []Node {
+	var nodePath []Node
+	for node := &currentNode; node != nil; node = node.parent {
+		nodePath = append(nodePath, *node)
+		// the parent may live in the nodePool of the search, which is reused
+		nodePath[len(nodePath)-1].parent = nil
+	}
 
-		nodePath = append(nodePath, parentNode)
-		currentNode = parentNode
+	// the chain starts at the goal, reverse it
+	for i, j := 0, len(nodePath)-1; i < j; i, j = i+1, j-1 {
+		nodePath[i], nodePath[j] = nodePath[j], nodePath[i]
 	}
 	return nodePath
 }
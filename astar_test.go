@@ -1,6 +1,11 @@
 package astar
 
 import (
+	"context"
+	"errors"
+	"math/rand"
+	"reflect"
+	"sync"
 	"testing"
 )
 
@@ -11,8 +16,8 @@ func TestAstar_H(t *testing.T) {
 	nodeD := Node{X: 2, Y: 5}
 
 	// invalid grid
-	_, err := New(Config{GridWidth: 1, GridHeight: 1})
-	if err == nil {
+	_, err := New(Config{GridWidth: 0, GridHeight: 1})
+	if err != ErrGridWidth {
 		t.Fatal("there should be a grid min error", err)
 	}
 
@@ -201,6 +206,21 @@ type TestContext struct {
 	blocks   []Node
 }
 
+// withoutInvalidNodes drops the weighted nodes which are invalid nodes as well, New rejects them
+func withoutInvalidNodes(weightedNodes, invalidNodes []Node) []Node {
+	invalid := make(map[[2]int]struct{}, len(invalidNodes))
+	for _, node := range invalidNodes {
+		invalid[nodeKey(node)] = struct{}{}
+	}
+	var nodes []Node
+	for _, node := range weightedNodes {
+		if _, ok := invalid[nodeKey(node)]; !ok {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
 func newContext(tarX, tarY, nearDist int, blocks []Node) IContext {
 	return &TestContext{
 		tarX:     tarX,
@@ -250,6 +270,7 @@ func TestAstar_ContextFindPathA(t *testing.T) {
 	}
 
 	pathNodesToFind := []Node{
+		{X: 1, Y: 1}, // the start node is part of the path
 		{X: 1, Y: 2},
 		{X: 0, Y: 2},
 		{X: 0, Y: 3},
@@ -260,6 +281,7 @@ func TestAstar_ContextFindPathA(t *testing.T) {
 	}
 
 	pathNodesToFindNear := []Node{
+		{X: 1, Y: 1},
 		{X: 1, Y: 2},
 		{X: 0, Y: 2},
 		{X: 0, Y: 3},
@@ -366,7 +388,25 @@ func TestAstar_ContextFindPathAMaxStep(t *testing.T) {
 	}
 	// 将提前跳出
 	foundPath, err := a.FindPathEx(ctx, startNode, endNode, 5)
+	if err != ErrStepLimitReached || foundPath != nil {
+		t.Error("step limit should be reached without a path", err, foundPath)
+	}
+
+	// the partial path is returned on request
+	a, err = New(Config{GridWidth: 5, GridHeight: 5, PartialPathOnStepLimit: true})
 	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, err = a.FindPathEx(ctx, startNode, endNode, 5)
+	if err != ErrStepLimitReached || len(foundPath) == 0 {
+		t.Error("there should be a partial path", err)
+	}
+	if foundPath[0].X != startNode.X || foundPath[0].Y != startNode.Y {
+		t.Error("partial path should start at the start node: ", foundPath)
+	}
+
+	// a limit above the needed expansions finds the path
+	if _, err = a.FindPathEx(ctx, startNode, endNode, 100); err != nil {
 		t.Error("there should be a path", err)
 	}
 
@@ -380,6 +420,93 @@ func TestAstar_ContextFindPathAMaxStep(t *testing.T) {
 	}
 }
 
+func TestAstar_FindPathFunc(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [ ] [ ] [E]   S: StartNode
+	// [S] [ ] [ ] [ ] [ ] [ ] [ ]   E: EndNode
+
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 6, Y: 1}
+
+	a, err := New(Config{GridWidth: 7, GridHeight: 2})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+
+	// stop after 3 expansions
+	lastF := 0
+	foundPath, err := a.FindPathFunc(nil, startNode, endNode, func(steps, minF int) bool {
+		if minF < lastF {
+			t.Error("min F should not decrease: ", minF, lastF)
+		}
+		lastF = minF
+		return steps < 3
+	})
+	if err != ErrSearchStopped {
+		t.Fatal("search should be stopped", err)
+	}
+	if len(foundPath) != 3 || foundPath[0].X != startNode.X || foundPath[0].Y != startNode.Y {
+		t.Error("partial path should lead from the start towards the end node: ", foundPath)
+	}
+	if last := foundPath[len(foundPath)-1]; ManhattanDistance(last, endNode) != 5 {
+		t.Error("partial path should end at the closest expanded node: ", foundPath)
+	}
+
+	// a callback which never stops finds the whole path
+	calls := 0
+	foundPath, err = a.FindPathFunc(nil, startNode, endNode, func(steps, minF int) bool {
+		calls++
+		return true
+	})
+	if err != nil || len(foundPath) != 8 {
+		t.Error("there should be a path", foundPath, err)
+	}
+	if calls == 0 {
+		t.Error("callback should be called")
+	}
+}
+
+func TestAstar_FindPathGreedy(t *testing.T) {
+
+	// [ ] [ ] [ ] [O] [ ] [E]   S: StartNode
+	// [ ] [ ] [ ] [ ] [O] [ ]   E: EndNode
+	// [ ] [ ] [ ] [ ] [ ] [ ]   O: ObstacleNode
+	// [ ] [O] [ ] [ ] [ ] [ ]
+	// [S] [ ] [ ] [ ] [O] [O]
+
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 5, Y: 4}
+	obstacleNodes := []Node{{X: 1, Y: 1}, {X: 3, Y: 4}, {X: 4, Y: 0}, {X: 4, Y: 3}, {X: 5, Y: 0}}
+
+	a, err := New(Config{GridWidth: 6, GridHeight: 5, InvalidNodes: obstacleNodes})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	_, cost, err := a.FindPathWithCost(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+
+	foundPath, err := a.FindPathGreedy(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a greedy path", err)
+	}
+	last := foundPath[len(foundPath)-1]
+	if last.X != endNode.X || last.Y != endNode.Y {
+		t.Error("path should end at the end node: ", foundPath)
+	}
+	for i := 1; i < len(foundPath); i++ {
+		if ManhattanDistance(foundPath[i-1], foundPath[i]) != 1 {
+			t.Error("path should move one cell per step: ", foundPath[i-1], foundPath[i])
+		}
+	}
+
+	// it heads for the top row first and has to go around the walls
+	if last.g <= cost {
+		t.Error("greedy path should be longer than the optimal one: ", last.g, cost)
+	}
+}
+
 func TestAstar_FindPathB(t *testing.T) {
 
 	// [ ] [ ] [ ] [ ] [ ]   S: StartNode
@@ -532,3 +659,2292 @@ func TestAstar_FindPathNoPath(t *testing.T) {
 	}
 
 }
+
+func TestAppendNeighborNodes(t *testing.T) {
+	a, err := New(Config{GridWidth: 4, GridHeight: 4, InvalidNodes: []Node{{X: 2, Y: 1}}})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+
+	buffer := []Node{{X: 9, Y: 9}}
+	neighbors := a.AppendNeighborNodes(nil, buffer, Node{X: 2, Y: 2})
+	if len(neighbors) != 4 || neighbors[0].X != 9 {
+		t.Error("the three neighbors should follow the nodes of dst: ", neighbors)
+	}
+
+	// the buffer is reused for the next node
+	neighbors = a.AppendNeighborNodes(nil, neighbors[:0], Node{X: 0, Y: 0})
+	if len(neighbors) != 2 || neighbors[0].Y != 1 || neighbors[1].X != 1 {
+		t.Error("the corner should have two neighbors in the reused buffer: ", neighbors)
+	}
+}
+
+func TestGetNeighborNodesDiagonal(t *testing.T) {
+
+	// setup a 4x4 grid with diagonal movement
+	a, err := New(Config{GridWidth: 4, GridHeight: 4, AllowDiagonal: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	wantList := NewList()
+
+	defer func() {
+		wantList.Clear()
+	}()
+
+	node := Node{X: 2, Y: 2}
+
+	validNeighbors := []Node{
+		{X: 2, Y: 3}, // up
+		{X: 2, Y: 1}, // down
+		{X: 1, Y: 2}, // left
+		{X: 3, Y: 2}, // right
+		{X: 3, Y: 3}, // up right
+		{X: 1, Y: 3}, // up left
+		{X: 3, Y: 1}, // down right
+		{X: 1, Y: 1}, // down left
+	}
+
+	wantList.Add(validNeighbors...)
+
+	neighbors := a.GetNeighborNodes(nil, node)
+	if len(neighbors) != len(validNeighbors) {
+		t.Error("unexpected neighbor count: ", len(neighbors))
+	}
+
+	for _, neighbor := range neighbors {
+		if wantList.Contains(neighbor) {
+			wantList.Remove(neighbor)
+		}
+	}
+
+	if !wantList.IsEmpty() {
+		t.Error("not all expected neighbors found: ", wantList.All())
+	}
+
+	// corner node only has 3 neighbors
+	if len(a.GetNeighborNodes(nil, Node{X: 0, Y: 0})) != 3 {
+		t.Error("corner node should have 3 neighbors")
+	}
+}
+
+func TestAstar_FindPathDiagonal(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [ ]   S: StartNode
+	// [ ] [ ] [ ] [E] [ ]   E: EndNode
+	// [ ] [ ] [P] [ ] [ ]   O: ObstacleNode
+	// [ ] [P] [ ] [ ] [ ]   P: Valid Path
+	// [S] [ ] [ ] [ ] [ ]
+
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 3, Y: 3}
+
+	pathNodesToFind := []Node{
+		{X: 0, Y: 0},
+		{X: 1, Y: 1},
+		{X: 2, Y: 2},
+		{X: 3, Y: 3},
+	}
+
+	pathList := NewList()
+
+	defer func() {
+		pathList.Clear()
+	}()
+
+	pathList.Add(pathNodesToFind...)
+
+	// setup a 5x5 grid
+	a, err := New(Config{GridWidth: 5, GridHeight: 5, AllowDiagonal: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, err := a.FindPath(nil, startNode, endNode)
+	if err != nil {
+		t.Error("there should be a path", err)
+	}
+
+	if len(foundPath) != len(pathNodesToFind) {
+		t.Error("unexpected path length: ", foundPath)
+	}
+
+	for _, pathNode := range foundPath {
+		if pathList.Contains(pathNode) {
+			pathList.Remove(pathNode)
+		}
+	}
+
+	if !pathList.IsEmpty() {
+		t.Error("not all expected path nodes found: ", pathList.All())
+	}
+}
+
+func TestGetNeighborNodesCornerCutting(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ]   N: Node
+	// [ ] [O] [D] [ ]   O: ObstacleNode (L-shaped wall)
+	// [ ] [N] [O] [ ]   D: Diagonal neighbor behind the corner
+	// [ ] [ ] [ ] [ ]
+
+	node := Node{X: 1, Y: 1}
+	cornerNode := Node{X: 2, Y: 2}
+	obstacleNodes := []Node{
+		{X: 1, Y: 2},
+		{X: 2, Y: 1},
+	}
+
+	// corner cutting allowed, the diagonal is a valid neighbor
+	a, err := New(Config{GridWidth: 4, GridHeight: 4, InvalidNodes: obstacleNodes, AllowDiagonal: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	neighborList := NewList()
+	neighborList.Add(a.GetNeighborNodes(nil, node)...)
+	if !neighborList.Contains(cornerNode) {
+		t.Error("corner node should be a neighbor: ", neighborList.All())
+	}
+
+	// corner cutting disallowed, the diagonal is pruned
+	a, err = New(Config{GridWidth: 4, GridHeight: 4, InvalidNodes: obstacleNodes, AllowDiagonal: true, DisallowCornerCutting: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	neighborList.Clear()
+	neighborList.Add(a.GetNeighborNodes(nil, node)...)
+	if neighborList.Contains(cornerNode) {
+		t.Error("corner node should not be a neighbor: ", neighborList.All())
+	}
+	// the other diagonals are still reachable
+	if !neighborList.Contains(Node{X: 0, Y: 0}) {
+		t.Error("down left node should be a neighbor: ", neighborList.All())
+	}
+
+	// the same wall given by the context
+	a, err = New(Config{GridWidth: 4, GridHeight: 4, AllowDiagonal: true, DisallowCornerCutting: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	ctx := newContext(3, 3, 0, obstacleNodes)
+	neighborList.Clear()
+	neighborList.Add(a.GetNeighborNodes(ctx, node)...)
+	if neighborList.Contains(cornerNode) {
+		t.Error("corner node should not be a neighbor: ", neighborList.All())
+	}
+}
+
+func TestAstar_GetNeighborNodesDiagonalPolicy(t *testing.T) {
+
+	// [ ] [ ] [D]   N: Node
+	// [ ] [N] [O]   O: ObstacleNode, D is beside a single wall
+	// [ ] [B] [C]   B: blocked by the context, C is between two blocked cells
+
+	node := Node{X: 1, Y: 1}
+	singleCornerNode := Node{X: 2, Y: 2}
+	obstacleNodes := []Node{{X: 2, Y: 1}}
+	ctx := newContext(2, 2, 2, []Node{{X: 1, Y: 0}})
+	closedCornerNode := Node{X: 2, Y: 0}
+
+	tests := []struct {
+		policy                     DiagonalPolicy
+		singleCorner, closedCorner bool
+	}{
+		{DiagonalAlways, true, true},
+		{DiagonalOnlyWhenNoObstacles, false, false},
+		{DiagonalNoCornerCutting, true, false},
+	}
+	for _, test := range tests {
+		a, err := New(Config{GridWidth: 3, GridHeight: 3, InvalidNodes: obstacleNodes, AllowDiagonal: true, DiagonalPolicy: test.policy})
+		if err != nil {
+			t.Fatal("there should be no error", err)
+		}
+		neighborList := NewList()
+		neighborList.Add(a.GetNeighborNodes(nil, node)...)
+		if neighborList.Contains(singleCornerNode) != test.singleCorner {
+			t.Error("the step past the single wall should follow the policy: ", test.policy, neighborList.All())
+		}
+		neighborList.Clear()
+		neighborList.Add(a.GetNeighborNodes(ctx, node)...)
+		if neighborList.Contains(closedCornerNode) != test.closedCorner {
+			t.Error("the step between the two walls should follow the policy: ", test.policy, neighborList.All())
+		}
+		// the free diagonal is never affected
+		if !neighborList.Contains(Node{X: 0, Y: 2}) {
+			t.Error("up left node should be a neighbor: ", test.policy, neighborList.All())
+		}
+	}
+
+	if _, err := New(Config{GridWidth: 3, GridHeight: 3, DisallowCornerCutting: true, DiagonalPolicy: DiagonalNoCornerCutting}); err == nil {
+		t.Error("the contradicting corner rules should be an error")
+	}
+	a, err := New(Config{GridWidth: 3, GridHeight: 3, AllowDiagonal: true, DisallowCornerCutting: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if a.config.DiagonalPolicy != DiagonalOnlyWhenNoObstacles {
+		t.Error("DisallowCornerCutting should select its policy: ", a.config.DiagonalPolicy)
+	}
+}
+
+func TestAstar_FindPathAccumulatedCost(t *testing.T) {
+
+	// [ ] [ ] [O] [ ] [E]   S: StartNode
+	// [ ] [ ] [O] [ ] [ ]   E: EndNode
+	// [ ] [ ] [ ] [O] [ ]   O: ObstacleNode
+	// [ ] [ ] [ ] [ ] [ ]
+	// [S] [ ] [ ] [O] [O]
+	//
+	// a greedy search runs into the pocket and returns a longer route
+
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 4, Y: 4}
+	obstacleNodes := []Node{
+		{X: 3, Y: 2},
+		{X: 4, Y: 0},
+		{X: 3, Y: 0},
+		{X: 2, Y: 4},
+		{X: 2, Y: 3},
+	}
+
+	// setup a 5x5 grid
+	a, err := New(Config{GridWidth: 5, GridHeight: 5, InvalidNodes: obstacleNodes})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, err := a.FindPath(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+
+	// the shortest route has 8 steps
+	if len(foundPath) != 9 {
+		t.Error("path should have 9 nodes: ", foundPath)
+	}
+	if foundPath[len(foundPath)-1].g != 8 || foundPath[len(foundPath)-1].f != 8 {
+		t.Error("end node should have accumulated G of 8: ", foundPath[len(foundPath)-1])
+	}
+}
+
+func TestAstar_FindPathRelaxOpenNodes(t *testing.T) {
+
+	// [ ] [O] [ ] [ ] [E]   S: StartNode
+	// [ ] [ ] [O] [ ] [ ]   E: EndNode
+	// [ ] [ ] [ ] [W] [ ]   O: ObstacleNode
+	// [ ] [W] [ ] [ ] [ ]   W: WeightedNode
+	// [S] [ ] [W] [O] [ ]
+	//
+	// some nodes are first reached over a weighted node
+	// and must be updated once the cheaper route is found
+
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 4, Y: 4}
+	obstacleNodes := []Node{
+		{X: 2, Y: 3},
+		{X: 3, Y: 0},
+		{X: 1, Y: 4},
+	}
+	weightedNodes := []Node{
+		{X: 1, Y: 1, Weighting: 1},
+		{X: 2, Y: 0, Weighting: 1},
+		{X: 3, Y: 2, Weighting: 5},
+	}
+
+	// setup a 5x5 grid
+	a, err := New(Config{GridWidth: 5, GridHeight: 5, InvalidNodes: obstacleNodes, WeightedNodes: weightedNodes})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, err := a.FindPath(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+
+	if foundPath[len(foundPath)-1].g != 9 {
+		t.Error("end node should have the optimal G of 9: ", foundPath[len(foundPath)-1])
+	}
+}
+
+func TestAstar_FindPathTwiceKeepsInvalidNodes(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [ ]   S: StartNode
+	// [ ] [ ] [ ] [ ] [ ]   E: EndNode
+	// [S] [O] [E] [ ] [ ]   O: ObstacleNode
+	// [ ] [ ] [ ] [ ] [ ]
+	// [ ] [ ] [ ] [ ] [ ]
+
+	startNode := Node{X: 0, Y: 2}
+	endNode := Node{X: 2, Y: 2}
+	obstacleNode := Node{X: 1, Y: 2}
+
+	// setup a 5x5 grid
+	a, err := New(Config{GridWidth: 5, GridHeight: 5, InvalidNodes: []Node{obstacleNode}})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		foundPath, err := a.FindPath(nil, startNode, endNode)
+		if err != nil {
+			t.Fatal("there should be a path", err)
+		}
+
+		pathList := NewList()
+		pathList.Add(foundPath...)
+		if pathList.Contains(obstacleNode) {
+			t.Error("the obstacle should be avoided in search ", i+1, foundPath)
+		}
+		if len(foundPath) != 5 {
+			t.Error("path should walk around the obstacle in search ", i+1, foundPath)
+		}
+	}
+}
+
+func TestAstar_FindPathWithCost(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [ ]   S: StartNode
+	// [ ] [ ] [ ] [ ] [ ]   E: EndNode
+	// [ ] [ ] [ ] [ ] [ ]   W: WeightedNode
+	// [ ] [ ] [ ] [ ] [ ]
+	// [S] [W] [E] [ ] [ ]
+
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 2, Y: 0}
+	weightedNodes := []Node{
+		{X: 1, Y: 0, Weighting: 1},
+	}
+
+	// setup a 5x5 grid
+	a, err := New(Config{GridWidth: 5, GridHeight: 5, WeightedNodes: weightedNodes})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+
+	// straight through the weighted node costs 3, same as walking around it
+	_, cost, err := a.FindPathWithCost(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if cost != 3 {
+		t.Error("cost should be 3: ", cost)
+	}
+
+	// diagonal steps are more expensive than orthogonal ones
+	a, err = New(Config{GridWidth: 5, GridHeight: 5, AllowDiagonal: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, cost, err := a.FindPathWithCost(nil, startNode, Node{X: 2, Y: 1})
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if len(foundPath) != 3 || cost != costStraight+costDiagonal {
+		t.Error("cost should be one orthogonal and one diagonal step: ", cost, foundPath)
+	}
+
+	// no path, no cost
+	a, err = New(Config{GridWidth: 5, GridHeight: 5, InvalidNodes: []Node{{X: 1, Y: 0}, {X: 0, Y: 1}}})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if _, cost, err = a.FindPathWithCost(nil, startNode, endNode); err == nil || cost != 0 {
+		t.Error("there should be no path", err, cost)
+	}
+}
+
+func TestAstar_FindPathContext(t *testing.T) {
+
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 49, Y: 49}
+
+	// setup a 50x50 grid
+	a, err := New(Config{GridWidth: 50, GridHeight: 50})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+
+	foundPath, err := a.FindPathContext(context.Background(), nil, startNode, endNode)
+	if err != nil || len(foundPath) == 0 {
+		t.Fatal("there should be a path", err)
+	}
+
+	// an already cancelled search stops early
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	foundPath, err = a.FindPathContext(cancelCtx, nil, startNode, endNode)
+	if !errors.Is(err, context.Canceled) {
+		t.Error("search should be cancelled", err)
+	}
+	if len(foundPath) > 0 {
+		t.Error("there should be no foundPath", foundPath)
+	}
+
+	// the cancelled search leaves no state behind
+	foundPath, err = a.FindPathContext(context.Background(), nil, startNode, endNode)
+	if err != nil || len(foundPath) == 0 {
+		t.Error("there should be a path after the cancelled search", err)
+	}
+}
+
+func TestAstar_FindPathDebug(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [ ]   S: StartNode
+	// [ ] [O] [ ] [E] [ ]   E: EndNode
+	// [ ] [ ] [O] [P] [ ]   O: ObstacleNode
+	// [ ] [S] [P] [P] [ ]   P: Valid Path
+	// [ ] [ ] [ ] [ ] [ ]
+
+	startNode := Node{X: 1, Y: 1}
+	endNode := Node{X: 3, Y: 3}
+	obstacleNodes := []Node{
+		{X: 1, Y: 3},
+		{X: 2, Y: 2},
+	}
+
+	// setup a 5x5 grid
+	a, err := New(Config{GridWidth: 5, GridHeight: 5, InvalidNodes: obstacleNodes})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, explored, err := a.FindPathDebug(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+
+	exploredList := NewList()
+	exploredList.Add(explored...)
+
+	if !exploredList.Contains(startNode) {
+		t.Error("the start node should be explored")
+	}
+	for _, pathNode := range foundPath {
+		if !exploredList.Contains(pathNode) {
+			t.Error("path node should be explored: ", pathNode)
+		}
+	}
+	for _, obstacleNode := range obstacleNodes {
+		if exploredList.Contains(obstacleNode) {
+			t.Error("obstacle node should not be explored: ", obstacleNode)
+		}
+	}
+	// the pooled parents of the search are not handed out
+	for _, node := range explored {
+		if node.parent != nil {
+			t.Error("explored node should not point into the search: ", node)
+		}
+	}
+}
+
+func TestAstar_FindPathWithObserver(t *testing.T) {
+	startNode := Node{X: 1, Y: 1}
+	endNode := Node{X: 3, Y: 3}
+	obstacleNodes := []Node{
+		{X: 1, Y: 3},
+		{X: 2, Y: 2},
+	}
+
+	a, err := New(Config{GridWidth: 5, GridHeight: 5, InvalidNodes: obstacleNodes})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	wantPath, explored, err := a.FindPathDebug(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+
+	var observed []Node
+	foundPath, err := a.FindPathWithObserver(nil, startNode, endNode, func(n Node) {
+		if n.parent != nil {
+			t.Error("observed node should not expose its parent: ", n)
+		}
+		observed = append(observed, n)
+	})
+	if err != nil || len(foundPath) != len(wantPath) {
+		t.Fatal("there should be the path of FindPath", err, foundPath)
+	}
+
+	// same order as FindPathDebug, the goal is the last one
+	if len(observed) != len(explored) {
+		t.Fatal("every expanded node should be observed: ", observed)
+	}
+	for i := range observed {
+		if observed[i].X != explored[i].X || observed[i].Y != explored[i].Y {
+			t.Error("nodes should be observed in the order of expansion: ", observed[i], explored[i])
+		}
+	}
+	if last := observed[len(observed)-1]; last.X != endNode.X || last.Y != endNode.Y {
+		t.Error("the end node should be observed last: ", last)
+	}
+
+	// without a callback it works like FindPath
+	if foundPath, err = a.FindPathWithObserver(nil, startNode, endNode, nil); err != nil || len(foundPath) != len(wantPath) {
+		t.Error("nil callback should find the path", err)
+	}
+}
+
+func TestAstar_FindPathAvoiding(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [ ]   S: StartNode
+	// [S] [P] [P] [P] [E]   E: EndNode
+	// [ ] [ ] [ ] [ ] [ ]   P: path of the first unit
+
+	startNode := Node{X: 0, Y: 1}
+	endNode := Node{X: 4, Y: 1}
+
+	a, err := New(Config{GridWidth: 5, GridHeight: 3})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	firstPath, err := a.FindPathAvoiding(nil, startNode, endNode, nil)
+	if err != nil || len(firstPath) != 5 {
+		t.Fatal("first unit should walk straight", err, firstPath)
+	}
+
+	// the second unit avoids the cells of the first one
+	penalty := make(map[[2]int]int)
+	for _, node := range firstPath[1 : len(firstPath)-1] {
+		penalty[[2]int{node.X, node.Y}] = 3
+	}
+	secondPath, err := a.FindPathAvoiding(nil, startNode, endNode, penalty)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	for _, node := range secondPath {
+		if _, ok := penalty[[2]int{node.X, node.Y}]; ok {
+			t.Error("second path should leave the congested cells: ", secondPath)
+		}
+	}
+	if last := secondPath[len(secondPath)-1]; last.g != 6 {
+		t.Error("detour should cost 6: ", last.g)
+	}
+	if len(penalty) != 3 {
+		t.Error("penalties should not be changed: ", penalty)
+	}
+
+	// the penalties are per query
+	if foundPath, err := a.FindPath(nil, startNode, endNode); err != nil || len(foundPath) != 5 {
+		t.Error("FindPath should ignore the penalties", err, foundPath)
+	}
+
+	// negative penalties are ignored
+	penalty = map[[2]int]int{{1, 0}: -10, {2, 0}: -10, {3, 0}: -10}
+	if foundPath, err := a.FindPathAvoiding(nil, startNode, endNode, penalty); err != nil || foundPath[len(foundPath)-1].g != 4 {
+		t.Error("negative penalties should be ignored", err, foundPath)
+	}
+}
+
+func TestAstar_FindPathStats(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [ ]   S: StartNode
+	// [ ] [O] [ ] [E] [ ]   E: EndNode
+	// [ ] [ ] [O] [ ] [ ]   O: ObstacleNode
+	// [ ] [S] [ ] [ ] [ ]
+	// [ ] [ ] [ ] [ ] [ ]
+
+	startNode := Node{X: 1, Y: 1}
+	endNode := Node{X: 3, Y: 3}
+	obstacleNodes := []Node{
+		{X: 1, Y: 3},
+		{X: 2, Y: 2},
+	}
+
+	a, err := New(Config{GridWidth: 5, GridHeight: 5, InvalidNodes: obstacleNodes})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	wantPath, explored, _ := a.FindPathDebug(nil, startNode, endNode)
+
+	foundPath, stats, err := a.FindPathStats(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if len(foundPath) != len(wantPath) || stats.PathLength != len(foundPath) {
+		t.Error("path should be the one of FindPath: ", stats, foundPath)
+	}
+	if stats.Expanded != len(explored) {
+		t.Error("expanded nodes should match the explored ones: ", stats.Expanded, len(explored))
+	}
+	if stats.MaxOpen < 1 || stats.MaxOpen > 25 {
+		t.Error("open list peak should be within the grid: ", stats.MaxOpen)
+	}
+
+	// no path, the corner is walled in
+	a.AddObstacle(1, 4)
+	a.AddObstacle(0, 3)
+	_, stats, err = a.FindPathStats(nil, startNode, Node{X: 0, Y: 4})
+	if err != ErrorNoPath || stats.PathLength != 0 || stats.Expanded == 0 {
+		t.Error("failed search should still report the expanded nodes: ", err, stats)
+	}
+}
+
+func TestAstar_MaxOpenNodes(t *testing.T) {
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 29, Y: 29}
+	config := Config{GridWidth: 30, GridHeight: 30, DisableHeuristic: true}
+
+	a, err := New(config)
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	_, stats, err := a.FindPathStats(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+
+	// the peak of the open list fits exactly
+	config.MaxOpenNodes = stats.MaxOpen
+	a, err = New(config)
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if _, err = a.FindPath(nil, startNode, endNode); err != nil {
+		t.Error("there should be a path within the limit", err)
+	}
+
+	// one node less aborts the search
+	config.MaxOpenNodes = stats.MaxOpen - 1
+	a, err = New(config)
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if foundPath, err := a.FindPath(nil, startNode, endNode); err != ErrOpenListOverflow || foundPath != nil {
+		t.Error("open list should overflow", err, foundPath)
+	}
+
+	config.MaxOpenNodes = -1
+	if _, err = New(config); err == nil {
+		t.Error("negative MaxOpenNodes should be invalid")
+	}
+}
+
+func TestAstar_CanEnter(t *testing.T) {
+	a, err := New(Config{
+		GridWidth:     5,
+		GridHeight:    5,
+		InvalidNodes:  []Node{{X: 1, Y: 1}},
+		WeightedNodes: []Node{{X: 2, Y: 2, Weighting: WeightImpassable}, {X: 3, Y: 3, Weighting: 5}},
+		SearchBounds:  &Rect{MinX: 0, MinY: 0, MaxX: 3, MaxY: 4},
+	})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	ctx := newContext(0, 0, 0, []Node{{X: 0, Y: 1}})
+
+	for _, c := range []struct {
+		x, y int
+		want bool
+	}{
+		{0, 0, true},
+		{3, 3, true},  // weighted
+		{1, 1, false}, // invalid node
+		{0, 1, false}, // blocked by the context
+		{2, 2, false}, // impassable weighting
+		{4, 0, false}, // outside the search bounds
+		{-1, 0, false},
+		{0, 5, false},
+	} {
+		if got := a.CanEnter(ctx, c.x, c.y); got != c.want {
+			t.Error("wrong result for cell: ", c.x, c.y, got)
+		}
+	}
+
+	a.AddObstacle(0, 0)
+	if a.CanEnter(nil, 0, 0) {
+		t.Error("added obstacle should not be enterable")
+	}
+
+	// the borders of a torus are joined
+	a, err = New(Config{GridWidth: 5, GridHeight: 5, WrapEdges: true, InvalidNodes: []Node{{X: 4, Y: 0}}})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if !a.CanEnter(nil, 5, 0) || a.CanEnter(nil, -1, 0) {
+		t.Error("coordinates should be wrapped")
+	}
+}
+
+func TestAstar_FindPathDiagonalOptimal(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for i := 0; i < 30; i++ {
+		var obstacleNodes []Node
+		for n := 0; n < 80; n++ {
+			if node := (Node{X: r.Intn(20), Y: r.Intn(20)}); node.X+node.Y > 0 && node.X+node.Y < 38 {
+				obstacleNodes = append(obstacleNodes, node)
+			}
+		}
+		config := Config{GridWidth: 20, GridHeight: 20, InvalidNodes: obstacleNodes, AllowDiagonal: true}
+		if i%2 == 1 {
+			config.OrthogonalCost, config.DiagonalCost = 10, 30
+		}
+
+		// dijkstra is always optimal
+		config.DisableHeuristic = true
+		a, err := New(config)
+		if err != nil {
+			t.Fatal("there should be no error", err)
+		}
+		_, wantCost, wantErr := a.FindPathWithCost(nil, Node{X: 0, Y: 0}, Node{X: 19, Y: 19})
+
+		config.DisableHeuristic = false
+		a, err = New(config)
+		if err != nil {
+			t.Fatal("there should be no error", err)
+		}
+		_, cost, err := a.FindPathWithCost(nil, Node{X: 0, Y: 0}, Node{X: 19, Y: 19})
+		if err != wantErr || cost != wantCost {
+			t.Error("default diagonal heuristic should find the optimal path: ", config.DiagonalCost, cost, wantCost, err)
+		}
+	}
+
+	// the chebyshev distance is the number of steps
+	a, err := New(Config{GridWidth: 20, GridHeight: 20, AllowDiagonal: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if h := a.H(Node{X: 0, Y: 0}, Node{X: 7, Y: 3}); h != 7 {
+		t.Error("H should be 7: ", h)
+	}
+}
+
+func TestAstar_FindPathDisableHeuristic(t *testing.T) {
+
+	// [ ] [O] [ ] [ ] [E]   S: StartNode
+	// [ ] [ ] [O] [ ] [ ]   E: EndNode
+	// [ ] [ ] [ ] [W] [ ]   O: ObstacleNode
+	// [ ] [W] [ ] [ ] [ ]   W: WeightedNode
+	// [S] [ ] [W] [O] [ ]
+
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 4, Y: 4}
+	config := Config{
+		GridWidth:  5,
+		GridHeight: 5,
+		InvalidNodes: []Node{
+			{X: 2, Y: 3},
+			{X: 3, Y: 0},
+			{X: 1, Y: 4},
+		},
+		WeightedNodes: []Node{
+			{X: 1, Y: 1, Weighting: 1},
+			{X: 2, Y: 0, Weighting: 1},
+			{X: 3, Y: 2, Weighting: 5},
+		},
+	}
+
+	a, err := New(config)
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	_, aStarExplored, err := a.FindPathDebug(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+
+	config.DisableHeuristic = true
+	a, err = New(config)
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, explored, err := a.FindPathDebug(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+
+	if foundPath[len(foundPath)-1].g != 9 || foundPath[len(foundPath)-1].h != 0 || foundPath[len(foundPath)-1].f != foundPath[len(foundPath)-1].g {
+		t.Error("end node should have the optimal G of 9 and no H: ", foundPath[len(foundPath)-1])
+	}
+	if len(explored) < len(aStarExplored) {
+		t.Error("dijkstra should not expand fewer nodes than a*", len(explored), len(aStarExplored))
+	}
+}
+
+func TestAstar_FindPathHeuristicWeight(t *testing.T) {
+
+	// 20x20 grid with a wall at X:10 from Y:0 to Y:15
+	// start and end are on both sides of the wall
+
+	startNode := Node{X: 5, Y: 0}
+	endNode := Node{X: 15, Y: 0}
+	var obstacleNodes []Node
+	for y := 0; y <= 15; y++ {
+		obstacleNodes = append(obstacleNodes, Node{X: 10, Y: y})
+	}
+
+	config := Config{GridWidth: 20, GridHeight: 20, InvalidNodes: obstacleNodes}
+	a, err := New(config)
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	defaultPath, defaultExplored, err := a.FindPathDebug(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+
+	// weight 1 is the default behavior
+	config.HeuristicWeight = 1
+	a, err = New(config)
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, explored, err := a.FindPathDebug(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if len(explored) != len(defaultExplored) || foundPath[len(foundPath)-1].g != defaultPath[len(defaultPath)-1].g {
+		t.Error("weight 1 should behave like the default")
+	}
+
+	// a higher weight expands fewer nodes
+	config.HeuristicWeight = 3
+	a, err = New(config)
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, explored, err = a.FindPathDebug(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if len(explored) >= len(defaultExplored) {
+		t.Error("weighted search should expand fewer nodes", len(explored), len(defaultExplored))
+	}
+	if foundPath[len(foundPath)-1].g < defaultPath[len(defaultPath)-1].g {
+		t.Error("weighted path cannot be shorter than the optimal one")
+	}
+
+	// negative weights are clamped to 0
+	config.HeuristicWeight = -2
+	a, err = New(config)
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if a.config.HeuristicWeight != 0 {
+		t.Error("negative weight should be clamped to 0")
+	}
+	foundPath, err = a.FindPath(nil, startNode, endNode)
+	if err != nil || foundPath[len(foundPath)-1].g != defaultPath[len(defaultPath)-1].g {
+		t.Error("weight 0 should find the optimal path", err)
+	}
+}
+
+func TestAstar_FindPathTieBreaking(t *testing.T) {
+
+	// empty 20x20 grid, many nodes share the same F value
+	startNode := Node{X: 2, Y: 3}
+	endNode := Node{X: 17, Y: 15}
+
+	explored := make(map[TieBreaking]int)
+	deviation := make(map[TieBreaking]int)
+	for _, tieBreaking := range []TieBreaking{TieBreakNone, TieBreakLowerH, TieBreakCrossProduct} {
+		a, err := New(Config{GridWidth: 20, GridHeight: 20, TieBreaking: tieBreaking})
+		if err != nil {
+			t.Fatal("there should be no error", err)
+		}
+		foundPath, exploredNodes, err := a.FindPathDebug(nil, startNode, endNode)
+		if err != nil {
+			t.Fatal("there should be a path", err)
+		}
+		if foundPath[len(foundPath)-1].g != 27 {
+			t.Error("path should be optimal", tieBreaking, foundPath[len(foundPath)-1])
+		}
+		explored[tieBreaking] = len(exploredNodes)
+		for _, pathNode := range foundPath {
+			if cross := crossProduct(pathNode, startNode, endNode); cross > deviation[tieBreaking] {
+				deviation[tieBreaking] = cross
+			}
+		}
+	}
+
+	if explored[TieBreakLowerH] >= explored[TieBreakNone] {
+		t.Error("lower H should expand fewer nodes", explored)
+	}
+	if explored[TieBreakCrossProduct] > explored[TieBreakLowerH] {
+		t.Error("cross product should not expand more nodes", explored)
+	}
+	if deviation[TieBreakCrossProduct] >= deviation[TieBreakLowerH] {
+		t.Error("cross product path should stay closer to the straight line", deviation)
+	}
+}
+
+func TestAstar_FindPathMoveCost(t *testing.T) {
+
+	// the ground rises to the east, steps uphill cost 4 and downhill 1
+	height := func(node Node) int {
+		return node.X
+	}
+	moveCost := func(from, to Node) int {
+		if height(to) > height(from) {
+			return 4
+		}
+		return 1
+	}
+
+	a, err := New(Config{GridWidth: 5, GridHeight: 3, MoveCost: moveCost, WeightedNodes: []Node{{X: 2, Y: 0, Weighting: 100}}})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	westNode, eastNode := Node{X: 0, Y: 0}, Node{X: 4, Y: 0}
+
+	// the WeightedNodes are ignored
+	if _, cost, err := a.FindPathWithCost(nil, westNode, eastNode); err != nil || cost != 16 {
+		t.Error("uphill cost should be 16: ", cost, err)
+	}
+	if _, cost, err := a.FindPathWithCost(nil, eastNode, westNode); err != nil || cost != 4 {
+		t.Error("downhill cost should be 4: ", cost, err)
+	}
+	foundPath, err := a.FindPathBidirectional(nil, westNode, eastNode)
+	if err != nil || foundPath[len(foundPath)-1].g != 16 {
+		t.Error("bidirectional uphill cost should be 16: ", foundPath, err)
+	}
+
+	// negative step costs are clamped
+	a, err = New(Config{GridWidth: 5, GridHeight: 3, DisableHeuristic: true, MoveCost: func(from, to Node) int {
+		return -5
+	}})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if _, cost, err := a.FindPathWithCost(nil, westNode, eastNode); err != nil || cost != 0 {
+		t.Error("cost should be 0: ", cost, err)
+	}
+}
+
+func TestAstar_FindPathCostFunc(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [ ]   S: StartNode
+	// [ ] [ ] [ ] [ ] [ ]   E: EndNode
+	// [ ] [ ] [ ] [ ] [ ]   W: expensive column from the CostFunc
+	// [ ] [ ] [W] [ ] [ ]   P: Valid Path
+	// [S] [ ] [W] [ ] [E]
+
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 4, Y: 0}
+
+	calls := 0
+	costFunc := func(x, y int) int {
+		calls++
+		if x == 2 && y < 2 {
+			return 10
+		}
+		return 0
+	}
+
+	// setup a 5x5 grid, the WeightedNodes are ignored
+	a, err := New(Config{
+		GridWidth:     5,
+		GridHeight:    5,
+		CostFunc:      costFunc,
+		WeightedNodes: []Node{{X: 2, Y: 2, Weighting: 100}},
+	})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, cost, err := a.FindPathWithCost(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+
+	// walking around the expensive cells costs 8
+	if cost != 8 {
+		t.Error("cost should be 8: ", cost, foundPath)
+	}
+	if calls == 0 {
+		t.Error("CostFunc should be called")
+	}
+	pathList := NewList()
+	pathList.Add(foundPath...)
+	if !pathList.Contains(Node{X: 2, Y: 2}) {
+		t.Error("path should cross at Y:2: ", foundPath)
+	}
+}
+
+func TestAstar_FindPathMulti(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [A]   S: StartNode
+	// [ ] [ ] [ ] [ ] [ ]   A, B, C: goal nodes
+	// [O] [O] [O] [O] [ ]   O: ObstacleNode
+	// [ ] [ ] [ ] [ ] [ ]
+	// [S] [ ] [ ] [ ] [B]   C is closer by distance but behind the wall
+	//                       B is the nearest reachable goal, then A
+
+	startNode := Node{X: 0, Y: 0}
+	goalNodes := []Node{
+		{X: 4, Y: 4}, // A
+		{X: 4, Y: 0}, // B
+		{X: 0, Y: 3}, // C
+	}
+	obstacleNodes := []Node{
+		{X: 0, Y: 2},
+		{X: 1, Y: 2},
+		{X: 2, Y: 2},
+		{X: 3, Y: 2},
+	}
+
+	// setup a 5x5 grid
+	a, err := New(Config{GridWidth: 5, GridHeight: 5, InvalidNodes: obstacleNodes})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, err := a.FindPathMulti(nil, startNode, goalNodes)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if foundPath[len(foundPath)-1].X != 4 || foundPath[len(foundPath)-1].Y != 0 {
+		t.Error("path should end at goal B: ", foundPath)
+	}
+	if len(foundPath) != 5 {
+		t.Error("path should have 5 nodes: ", foundPath)
+	}
+
+	// without B the path leads to A, C is further away around the wall
+	foundPath, err = a.FindPathMulti(nil, startNode, []Node{goalNodes[2], goalNodes[0]})
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if foundPath[len(foundPath)-1].X != 4 || foundPath[len(foundPath)-1].Y != 4 || foundPath[len(foundPath)-1].g != 8 {
+		t.Error("path should end at goal A: ", foundPath)
+	}
+
+	if _, err = a.FindPathMulti(nil, startNode, nil); err == nil {
+		t.Error("there should be an error without goals")
+	}
+
+	// a normal search afterwards is not affected
+	foundPath, err = a.FindPath(nil, startNode, goalNodes[2])
+	if err != nil || foundPath[len(foundPath)-1].X != 0 || foundPath[len(foundPath)-1].Y != 3 {
+		t.Error("path should end at goal C: ", foundPath, err)
+	}
+}
+
+func TestAstar_FindPathMultiSource(t *testing.T) {
+
+	// [E] [ ] [ ] [B] [ ]   E: EndNode
+	// [ ] [ ] [ ] [ ] [C]   A, B, C: start nodes
+	// [O] [O] [O] [O] [ ]   O: ObstacleNode
+	// [A] [ ] [ ] [ ] [ ]   A is closer by distance but behind the wall
+	// [ ] [ ] [ ] [ ] [ ]   B is the nearest start which reaches E, then C
+
+	endNode := Node{X: 0, Y: 4}
+	startNodes := []Node{
+		{X: 0, Y: 1}, // A
+		{X: 3, Y: 4}, // B
+		{X: 4, Y: 3}, // C
+	}
+	obstacleNodes := []Node{
+		{X: 0, Y: 2},
+		{X: 1, Y: 2},
+		{X: 2, Y: 2},
+		{X: 3, Y: 2},
+	}
+
+	a, err := New(Config{GridWidth: 5, GridHeight: 5, InvalidNodes: obstacleNodes})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, startNode, err := a.FindPathMultiSource(nil, startNodes, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if startNode != startNodes[1] || foundPath[0].X != 3 || foundPath[0].Y != 4 {
+		t.Error("path should begin at start B: ", startNode, foundPath)
+	}
+	if len(foundPath) != 4 || foundPath[len(foundPath)-1].g != 3 {
+		t.Error("path should have 4 nodes: ", foundPath)
+	}
+
+	// without B the path begins at C, the cost is the one of a single search from C
+	foundPath, startNode, err = a.FindPathMultiSource(nil, []Node{startNodes[0], startNodes[2]}, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	_, cost, err := a.FindPathWithCost(nil, startNodes[2], endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if startNode != startNodes[2] || foundPath[len(foundPath)-1].g != cost {
+		t.Error("path should begin at start C: ", startNode, foundPath, cost)
+	}
+
+	if _, _, err = a.FindPathMultiSource(nil, nil, endNode); err == nil {
+		t.Error("there should be an error without start nodes")
+	}
+	if _, _, err = a.FindPathMultiSource(nil, []Node{startNodes[1], {X: 1, Y: 2}}, endNode); err != ErrStartBlocked {
+		t.Error("a blocked start node should be an error: ", err)
+	}
+}
+
+func TestAstar_FindPathIncludesStart(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [ ]   S: StartNode
+	// [ ] [E] [P] [ ] [ ]   E: EndNode
+	// [ ] [O] [P] [O] [O]   O: ObstacleNode
+	// [ ] [O] [S] [ ] [ ]   P: Valid Path
+	// [ ] [ ] [ ] [ ] [ ]
+
+	startNode := Node{X: 2, Y: 1}
+	endNode := Node{X: 1, Y: 3}
+	obstacleNodes := []Node{
+		{X: 3, Y: 2},
+		{X: 4, Y: 2},
+		{X: 1, Y: 1},
+		{X: 1, Y: 2},
+	}
+
+	wantPath := []Node{
+		{X: 2, Y: 1},
+		{X: 2, Y: 2},
+		{X: 2, Y: 3},
+		{X: 1, Y: 3},
+	}
+
+	// setup a 5x5 grid
+	a, err := New(Config{GridWidth: 5, GridHeight: 5, InvalidNodes: obstacleNodes})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, err := a.FindPath(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+
+	if len(foundPath) != len(wantPath) {
+		t.Fatal("unexpected path length: ", foundPath)
+	}
+	if foundPath[0].X != startNode.X || foundPath[0].Y != startNode.Y {
+		t.Error("path[0] should be the start node: ", foundPath)
+	}
+	if last := foundPath[len(foundPath)-1]; last.X != endNode.X || last.Y != endNode.Y {
+		t.Error("the last node should be the end node: ", foundPath)
+	}
+	for i, wantNode := range wantPath {
+		if foundPath[i].X != wantNode.X || foundPath[i].Y != wantNode.Y {
+			t.Error("unexpected node on index ", i, foundPath)
+		}
+	}
+}
+
+func TestAstar_FindPathConcurrent(t *testing.T) {
+	obstacleNodes := []Node{
+		{X: 5, Y: 1},
+		{X: 5, Y: 2},
+		{X: 5, Y: 3},
+		{X: 5, Y: 4},
+		{X: 5, Y: 5},
+	}
+	a, err := New(Config{GridWidth: 20, GridHeight: 20, InvalidNodes: obstacleNodes, AllowDiagonal: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+
+	startNode := Node{X: 0, Y: 3}
+	endNode := Node{X: 10, Y: 3}
+	_, wantCost, err := a.FindPathWithCost(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+
+	// every goroutine searches with the same PathFinder
+	var wg sync.WaitGroup
+	costs := make([]int, 50)
+	errs := make([]error, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, costs[i], errs[i] = a.FindPathWithCost(nil, startNode, endNode)
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range costs {
+		if errs[i] != nil || costs[i] != wantCost {
+			t.Error("concurrent search should find the same path", i, errs[i], costs[i], wantCost)
+		}
+	}
+}
+
+func TestAstar_FindPathOrigin(t *testing.T) {
+
+	// grid from -2/10 to 2/14
+	// [ ] [ ] [ ] [ ] [E]   S: StartNode
+	// [ ] [ ] [ ] [ ] [ ]   E: EndNode
+	// [O] [O] [O] [O] [ ]   O: ObstacleNode
+	// [ ] [ ] [ ] [ ] [ ]
+	// [S] [ ] [ ] [ ] [ ]
+
+	startNode := Node{X: -2, Y: 10}
+	endNode := Node{X: 2, Y: 14}
+	obstacleNodes := []Node{
+		{X: -2, Y: 12},
+		{X: -1, Y: 12},
+		{X: 0, Y: 12},
+		{X: 1, Y: 12},
+	}
+
+	a, err := New(Config{GridWidth: 5, GridHeight: 5, OriginX: -2, OriginY: 10, InvalidNodes: obstacleNodes})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+
+	// the corner cell has two neighbors inside the shifted grid
+	if neighbors := a.GetNeighborNodes(nil, startNode); len(neighbors) != 2 {
+		t.Error("corner should have two neighbors: ", neighbors)
+	}
+
+	foundPath, err := a.FindPath(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if len(foundPath) != 9 {
+		t.Error("path should have 9 nodes: ", foundPath)
+	}
+	if last := foundPath[len(foundPath)-1]; last.X != endNode.X || last.Y != endNode.Y {
+		t.Error("path should end at the end node: ", foundPath)
+	}
+
+	// cells of the unshifted grid are outside
+	if _, err = a.FindPath(nil, startNode, Node{X: 3, Y: 3}); err != ErrOutOfBounds {
+		t.Error("end node should be out of bounds", err)
+	}
+}
+
+func TestAstar_FindPathWithinCost(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [E]   S: StartNode
+	// [ ] [ ] [ ] [ ] [ ]   E: EndNode
+	// [ ] [ ] [O] [O] [O]   O: ObstacleNode
+	// [ ] [ ] [ ] [ ] [ ]
+	// [S] [ ] [ ] [ ] [ ]
+
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 4, Y: 4}
+	obstacleNodes := []Node{
+		{X: 2, Y: 2},
+		{X: 3, Y: 2},
+		{X: 4, Y: 2},
+	}
+
+	a, err := New(Config{GridWidth: 5, GridHeight: 5, InvalidNodes: obstacleNodes})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	_, wantCost, err := a.FindPathWithCost(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+
+	// exactly the budget
+	foundPath, err := a.FindPathWithinCost(nil, startNode, endNode, wantCost)
+	if err != nil {
+		t.Fatal("there should be a path within the budget", err)
+	}
+	if foundPath[len(foundPath)-1].g != wantCost {
+		t.Error("path should have the optimal cost: ", wantCost, foundPath)
+	}
+
+	// one below the budget
+	if _, err = a.FindPathWithinCost(nil, startNode, endNode, wantCost-1); err != ErrorNoPath {
+		t.Error("there should be no path within the budget", err)
+	}
+
+	// a near target is reachable with a small budget
+	if _, err = a.FindPathWithinCost(nil, startNode, Node{X: 1, Y: 1}, 2); err != nil {
+		t.Error("there should be a path within the budget", err)
+	}
+
+	// the start node is always within budget
+	if _, err = a.FindPathWithinCost(nil, startNode, startNode, 0); err != nil {
+		t.Error("start node should be reachable", err)
+	}
+	if _, err = a.FindPathWithinCost(nil, startNode, startNode, -1); err != ErrorNoPath {
+		t.Error("negative budget should find no path", err)
+	}
+}
+
+func TestAstar_FindPathReusesSearch(t *testing.T) {
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 4, Y: 4}
+
+	a, err := New(Config{GridWidth: 5, GridHeight: 5})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+
+	// the goals of a multi goal search are not kept for the next search
+	if _, err = a.FindPathMulti(nil, startNode, []Node{{X: 1, Y: 0}}); err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	foundPath, err := a.FindPath(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if last := foundPath[len(foundPath)-1]; last.X != endNode.X || last.Y != endNode.Y {
+		t.Error("path should end at the end node: ", foundPath)
+	}
+
+	// the stats start from zero again
+	_, stats, _ := a.FindPathStats(nil, startNode, endNode)
+	_, againStats, _ := a.FindPathStats(nil, startNode, endNode)
+	if stats != againStats {
+		t.Error("repeated search should have the same stats: ", stats, againStats)
+	}
+}
+
+func TestAstar_FindPathNeighbors(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [ ] [O] [ ] [ ] [ ] [ ]   S: StartNode
+	// [S] [A] [ ] [ ] [ ] [O] [ ] [ ] [B] [E]   E: EndNode
+	// [ ] [ ] [ ] [ ] [ ] [O] [ ] [ ] [ ] [ ]   O: ObstacleNode
+	//                                           A, B: Portal
+
+	startNode := Node{X: 0, Y: 1}
+	endNode := Node{X: 9, Y: 1}
+	obstacleNodes := []Node{
+		{X: 5, Y: 0},
+		{X: 5, Y: 1},
+		{X: 5, Y: 2},
+	}
+	portals := map[[2]int]Node{
+		{1, 1}: {X: 8, Y: 1},
+		{8, 1}: {X: 1, Y: 1},
+	}
+
+	config := Config{GridWidth: 10, GridHeight: 3, InvalidNodes: obstacleNodes}
+	a, err := New(config)
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if _, err = a.FindPath(nil, startNode, endNode); err != ErrorNoPath {
+		t.Fatal("the wall should block the grid", err)
+	}
+
+	config.Neighbors = func(ctx IContext, node Node) []Node {
+		neighbors := []Node{
+			{X: node.X + 1, Y: node.Y},
+			{X: node.X - 1, Y: node.Y},
+			{X: node.X, Y: node.Y + 1},
+			{X: node.X, Y: node.Y - 1},
+			// blocked and out of bound candidates are dropped
+			{X: 5, Y: 1},
+			{X: -1, Y: 0},
+		}
+		if target, ok := portals[[2]int{node.X, node.Y}]; ok {
+			neighbors = append(neighbors, target)
+		}
+		return neighbors
+	}
+	a, err = New(config)
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+
+	for _, neighbor := range a.GetNeighborNodes(nil, Node{X: 0, Y: 0}) {
+		if neighbor.X == 5 || neighbor.X < 0 || neighbor.Y < 0 {
+			t.Error("inaccessible candidate should be dropped: ", neighbor)
+		}
+	}
+
+	foundPath, cost, err := a.FindPathWithCost(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path through the portal", err)
+	}
+	// S -> A -> B -> E
+	if len(foundPath) != 4 || cost != 3 {
+		t.Error("path should use the portal: ", cost, foundPath)
+	}
+
+	// the bidirectional search uses FindPath for custom neighbors
+	if foundPath, err = a.FindPathBidirectional(nil, startNode, endNode); err != nil || len(foundPath) != 4 {
+		t.Error("bidirectional search should use the portal: ", err, foundPath)
+	}
+}
+
+func TestAstar_FindPathNeighborWeighting(t *testing.T) {
+
+	// [ ] [ ] [ ]   S: StartNode
+	// [S] [W] [E]   E: EndNode
+	// [ ] [ ] [ ]   W: Weighting of the candidate
+
+	startNode := Node{X: 0, Y: 1}
+	endNode := Node{X: 2, Y: 1}
+
+	weighting := 0
+	config := Config{GridWidth: 3, GridHeight: 3}
+	config.Neighbors = func(ctx IContext, node Node) []Node {
+		neighbors := []Node{
+			{X: node.X + 1, Y: node.Y},
+			{X: node.X - 1, Y: node.Y},
+			{X: node.X, Y: node.Y + 1},
+			{X: node.X, Y: node.Y - 1},
+		}
+		for i := range neighbors {
+			if neighbors[i].X == 1 && neighbors[i].Y == 1 {
+				neighbors[i].Weighting = weighting
+			}
+		}
+		return neighbors
+	}
+	a, err := New(config)
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+
+	// the straight line through the middle
+	foundPath, cost, err := a.FindPathWithCost(nil, startNode, endNode)
+	if err != nil || cost != 2 || foundPath[1].X != 1 || foundPath[1].Y != 1 {
+		t.Error("path should go straight: ", err, cost, foundPath)
+	}
+
+	// the weighted middle cell is avoided
+	weighting = 5
+	foundPath, cost, err = a.FindPathWithCost(nil, startNode, endNode)
+	if err != nil || cost != 4 || len(foundPath) != 5 {
+		t.Error("path should avoid the weighted node: ", err, cost, foundPath)
+	}
+}
+
+func TestAstar_FindPathReached(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [ ]   S: StartNode
+	// [ ] [ ] [ ] [E] [ ]   E: EndNode, blocked by the context
+	// [ ] [ ] [ ] [ ] [ ]
+	// [ ] [S] [ ] [ ] [ ]
+	// [ ] [ ] [ ] [ ] [ ]
+
+	startNode := Node{X: 1, Y: 1}
+	endNode := Node{X: 3, Y: 3}
+
+	a, err := New(Config{GridWidth: 5, GridHeight: 5})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+
+	foundPath, reachedExact, err := a.FindPathReached(nil, startNode, endNode)
+	if err != nil || !reachedExact || len(foundPath) != 5 {
+		t.Error("path should reach the end node", err, foundPath)
+	}
+
+	// the end node is blocked but its neighbors are near enough
+	ctx := newContext(endNode.X, endNode.Y, 1, []Node{endNode})
+	foundPath, reachedExact, err = a.FindPathReached(ctx, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path to a near node", err)
+	}
+	last := foundPath[len(foundPath)-1]
+	if reachedExact || AbsI(last.X-endNode.X)+AbsI(last.Y-endNode.Y) != 1 {
+		t.Error("path should end next to the end node: ", foundPath)
+	}
+	// the search heads straight for the end node
+	if len(foundPath) != 4 {
+		t.Error("path to the near node should be the shortest one: ", foundPath)
+	}
+
+	// no near node is reachable
+	ctx = newContext(endNode.X, endNode.Y, 0, []Node{endNode})
+	if _, _, err = a.FindPathReached(ctx, startNode, endNode); err != ErrorNoPath {
+		t.Error("there should be no path", err)
+	}
+}
+
+func TestAstar_FindPathHugeWeighting(t *testing.T) {
+
+	// [ ] [ ] [ ]   S: StartNode
+	// [S] [W] [E]   E: EndNode
+	// [ ] [ ] [ ]   W: WeightedNode
+
+	startNode := Node{X: 0, Y: 1}
+	endNode := Node{X: 2, Y: 1}
+
+	// a huge weight is avoided if there is another way
+	a, err := New(Config{GridWidth: 3, GridHeight: 3, WeightedNodes: []Node{{X: 1, Y: 1, Weighting: WeightImpassable}}})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, cost, err := a.FindPathWithCost(nil, startNode, endNode)
+	if err != nil || cost != 4 {
+		t.Error("path should go around the weighted node: ", err, cost, foundPath)
+	}
+
+	// the only way through the impassable node
+	a, err = New(Config{GridWidth: 3, GridHeight: 2, WeightedNodes: []Node{
+		{X: 1, Y: 0, Weighting: WeightImpassable},
+		{X: 1, Y: 1, Weighting: WeightImpassable},
+	}})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if _, err = a.FindPath(nil, Node{X: 0, Y: 0}, Node{X: 2, Y: 0}); err != ErrorNoPath {
+		t.Error("impassable node should block the path", err)
+	}
+
+	// two huge weights in a row do not wrap around
+	a, err = New(Config{GridWidth: 4, GridHeight: 2, WeightedNodes: []Node{
+		{X: 1, Y: 0, Weighting: WeightImpassable / 2},
+		{X: 1, Y: 1, Weighting: WeightImpassable / 2},
+		{X: 2, Y: 0, Weighting: WeightImpassable / 2},
+		{X: 2, Y: 1, Weighting: WeightImpassable / 2},
+	}})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if _, explored, err := a.FindPathDebug(nil, Node{X: 0, Y: 0}, Node{X: 3, Y: 0}); err != ErrorNoPath {
+		t.Error("saturated path should be impassable", err)
+	} else {
+		for _, node := range explored {
+			if node.g < 0 || node.f < 0 {
+				t.Error("costs should not wrap around: ", node)
+			}
+		}
+	}
+
+	// a single huge but finite weight is still passable
+	a, err = New(Config{GridWidth: 3, GridHeight: 2, WeightedNodes: []Node{
+		{X: 1, Y: 0, Weighting: WeightImpassable / 2},
+		{X: 1, Y: 1, Weighting: WeightImpassable / 2},
+	}})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if _, cost, err = a.FindPathWithCost(nil, Node{X: 0, Y: 0}, Node{X: 2, Y: 0}); err != nil || cost <= 0 {
+		t.Error("huge weight should be the last option", err, cost)
+	}
+}
+
+func TestAstar_FindPathWeightedRegions(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ]   S: StartNode
+	// [ ] [ ] [ ] [M] [M] [M] [M] [ ] [ ] [ ]   E: EndNode
+	// [S] [ ] [ ] [M] [M] [M] [M] [ ] [ ] [E]   M: Mud zone
+	// [ ] [ ] [ ] [M] [M] [M] [M] [ ] [ ] [ ]
+	// [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ]
+
+	startNode := Node{X: 0, Y: 2}
+	endNode := Node{X: 9, Y: 2}
+	mud := Rect{MinX: 3, MinY: 1, MaxX: 6, MaxY: 3}
+
+	a, err := New(Config{GridWidth: 10, GridHeight: 5, WeightedRegions: []WeightedRect{{Bounds: mud, Weighting: 5}}})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, cost, err := a.FindPathWithCost(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	for _, node := range foundPath {
+		if mud.Contains(node.X, node.Y) {
+			t.Error("path should go around the mud zone: ", foundPath)
+		}
+	}
+	// 9 steps plus 2 up and 2 down
+	if cost != 13 {
+		t.Error("path should cost 13: ", cost)
+	}
+
+	// the same zone as single WeightedNodes
+	var weightedNodes []Node
+	for x := mud.MinX; x <= mud.MaxX; x++ {
+		for y := mud.MinY; y <= mud.MaxY; y++ {
+			weightedNodes = append(weightedNodes, Node{X: x, Y: y, Weighting: 5})
+		}
+	}
+	a, err = New(Config{GridWidth: 10, GridHeight: 5, WeightedNodes: weightedNodes})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if _, wantCost, err := a.FindPathWithCost(nil, startNode, endNode); err != nil || wantCost != cost {
+		t.Error("region should cost like its WeightedNodes: ", wantCost, cost, err)
+	}
+
+	// overlapping regions add up, a light zone is crossed
+	a, err = New(Config{GridWidth: 10, GridHeight: 5, WeightedRegions: []WeightedRect{
+		{Bounds: mud, Weighting: 1},
+		{Bounds: Rect{MinX: 0, MinY: 0, MaxX: 9, MaxY: 0}, Weighting: 1},
+		{Bounds: Rect{MinX: 0, MinY: 4, MaxX: 9, MaxY: 4}, Weighting: 1},
+	}})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if foundPath, cost, err = a.FindPathWithCost(nil, startNode, endNode); err != nil || cost != 13 || len(foundPath) != 10 {
+		t.Error("path should cross the light mud zone: ", cost, foundPath, err)
+	}
+}
+
+func TestAstar_FindPathDiagonalCost(t *testing.T) {
+
+	// [ ] [E]   S: StartNode
+	// [S] [ ]   E: EndNode
+
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 1, Y: 1}
+
+	// a single diagonal beats two orthogonal moves
+	a, err := New(Config{GridWidth: 2, GridHeight: 2, AllowDiagonal: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, cost, err := a.FindPathWithCost(nil, startNode, endNode)
+	if err != nil || len(foundPath) != 2 || cost != 14 {
+		t.Error("path should be one diagonal step: ", err, cost, foundPath)
+	}
+
+	// custom costs
+	a, err = New(Config{GridWidth: 2, GridHeight: 2, AllowDiagonal: true, OrthogonalCost: 2, DiagonalCost: 3})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if foundPath, cost, err = a.FindPathWithCost(nil, startNode, endNode); err != nil || len(foundPath) != 2 || cost != 3 {
+		t.Error("path should be one diagonal step: ", err, cost, foundPath)
+	}
+
+	// a diagonal more expensive than two orthogonal moves is avoided
+	a, err = New(Config{GridWidth: 2, GridHeight: 2, AllowDiagonal: true, DiagonalCost: 25})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if foundPath, cost, err = a.FindPathWithCost(nil, startNode, endNode); err != nil || len(foundPath) != 3 || cost != 20 {
+		t.Error("path should take two orthogonal steps: ", err, cost, foundPath)
+	}
+	if foundPath, err = a.FindPathJPS(nil, startNode, endNode); err != nil || len(foundPath) != 3 {
+		t.Error("jump point search should fall back to FindPath: ", err, foundPath)
+	}
+
+	if _, err = New(Config{GridWidth: 2, GridHeight: 2, DiagonalCost: -1}); err == nil {
+		t.Error("negative costs should be invalid")
+	}
+}
+
+func TestAstar_Reconfigure(t *testing.T) {
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 2, Y: 0}
+
+	a, err := New(Config{GridWidth: 3, GridHeight: 2, InvalidNodes: []Node{{X: 1, Y: 0}, {X: 1, Y: 1}}})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if _, err = a.FindPath(nil, startNode, endNode); err != ErrorNoPath {
+		t.Fatal("the wall should block the path", err)
+	}
+
+	// the old invalid nodes are dropped
+	if err = a.Reconfigure(Config{GridWidth: 3, GridHeight: 2, InvalidNodes: []Node{{X: 1, Y: 1}}}); err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, err := a.FindPath(nil, startNode, endNode)
+	if err != nil || len(foundPath) != 3 {
+		t.Error("there should be a path below the wall", err, foundPath)
+	}
+
+	// an invalid config keeps the old one
+	if err = a.Reconfigure(Config{GridWidth: 3, GridHeight: 0}); err != ErrGridHeight {
+		t.Error("GridHeight 0 should be invalid", err)
+	}
+	if foundPath, err = a.FindPath(nil, startNode, endNode); err != nil || len(foundPath) != 3 {
+		t.Error("old config should stay in place", err, foundPath)
+	}
+
+	// a bigger grid with diagonal movement
+	if err = a.Reconfigure(Config{GridWidth: 5, GridHeight: 5, AllowDiagonal: true}); err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if _, cost, err := a.FindPathWithCost(nil, startNode, Node{X: 4, Y: 4}); err != nil || cost != 4*costDiagonal {
+		t.Error("path should use the new config", err, cost)
+	}
+}
+
+func TestAstar_AddRemoveObstacle(t *testing.T) {
+
+	// [ ] [O] [ ]   S: StartNode
+	// [S] [O] [E]   E: EndNode
+	//               O: ObstacleNode
+
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 2, Y: 0}
+
+	a, err := New(Config{GridWidth: 3, GridHeight: 2, InvalidNodes: []Node{{X: 1, Y: 0}, {X: 1, Y: 1}}})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if _, err = a.FindPath(nil, startNode, endNode); err != ErrorNoPath {
+		t.Fatal("the wall should block the path", err)
+	}
+
+	// the door opens
+	a.RemoveObstacle(1, 1)
+	foundPath, err := a.FindPath(nil, startNode, endNode)
+	if err != nil || len(foundPath) != 5 {
+		t.Error("there should be a path through the door", err, foundPath)
+	}
+
+	// and closes again
+	a.AddObstacle(1, 1)
+	if _, err = a.FindPath(nil, startNode, endNode); err != ErrorNoPath {
+		t.Error("the closed door should block the path", err)
+	}
+
+	// changes while other goroutines search
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.FindPath(nil, startNode, endNode)
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		a.RemoveObstacle(1, 1)
+		a.AddObstacle(1, 1)
+	}
+	wg.Wait()
+}
+
+func TestAstar_IsReachable(t *testing.T) {
+
+	// [ ] [O] [ ]   S: StartNode
+	// [S] [O] [E]   E: EndNode
+	//               O: ObstacleNode
+
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 2, Y: 0}
+
+	a, err := New(Config{GridWidth: 3, GridHeight: 2, InvalidNodes: []Node{{X: 1, Y: 0}, {X: 1, Y: 1}}})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if reachable, err := a.IsReachable(nil, startNode, endNode); reachable || err != nil {
+		t.Error("end node should not be reachable", reachable, err)
+	}
+	if reachable, err := a.IsReachable(nil, startNode, Node{X: 0, Y: 1}); !reachable || err != nil {
+		t.Error("neighbor should be reachable", reachable, err)
+	}
+
+	a.RemoveObstacle(1, 1)
+	if reachable, err := a.IsReachable(nil, startNode, endNode); !reachable || err != nil {
+		t.Error("end node should be reachable through the door", reachable, err)
+	}
+}
+
+func TestAstar_FindPathValidateEndpoints(t *testing.T) {
+
+	// [ ] [ ] [ ]   O: ObstacleNode
+	// [ ] [O] [ ]   X: blocked by the context
+	// [ ] [ ] [X]
+
+	a, err := New(Config{GridWidth: 3, GridHeight: 3, InvalidNodes: []Node{{X: 1, Y: 1}}})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	ctx := newContext(2, 0, -1, []Node{{X: 2, Y: 0}})
+
+	tests := []struct {
+		startNode, endNode Node
+		want               error
+	}{
+		{Node{X: -1, Y: 0}, Node{X: 2, Y: 2}, ErrOutOfBounds},
+		{Node{X: 0, Y: 0}, Node{X: 3, Y: 2}, ErrOutOfBounds},
+		{Node{X: 1, Y: 1}, Node{X: 2, Y: 2}, ErrStartBlocked},
+		{Node{X: 0, Y: 0}, Node{X: 1, Y: 1}, ErrEndBlocked},
+		{Node{X: 2, Y: 0}, Node{X: 2, Y: 2}, ErrStartBlocked},
+		{Node{X: 0, Y: 0}, Node{X: 2, Y: 0}, ErrEndBlocked},
+		{Node{X: 0, Y: 0}, Node{X: 2, Y: 2}, nil},
+	}
+	for _, test := range tests {
+		if _, err := a.FindPath(ctx, test.startNode, test.endNode); err != test.want {
+			t.Error("wrong error: ", test.startNode, test.endNode, err, test.want)
+		}
+		if _, err := a.FindPathBidirectional(ctx, test.startNode, test.endNode); err != test.want {
+			t.Error("wrong bidirectional error: ", test.startNode, test.endNode, err, test.want)
+		}
+	}
+
+	// a blocked end node accepted by IsNearEnough is searched
+	ctx = newContext(2, 0, 1, []Node{{X: 2, Y: 0}})
+	if _, err = a.FindPath(ctx, Node{X: 0, Y: 0}, Node{X: 2, Y: 0}); err != nil {
+		t.Error("there should be a path to a near node", err)
+	}
+}
+
+func TestAstar_FindPathAllowBlockedEndpoints(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [ ]   S: StartNode
+	// [ ] [O] [O] [O] [ ]   E: EndNode
+	// [ ] [O] [S] [O] [E]   O: ObstacleNode
+	// [ ] [O] [ ] [O] [ ]
+	// [ ] [ ] [ ] [ ] [ ]
+
+	startNode := Node{X: 2, Y: 2}
+	endNode := Node{X: 4, Y: 2}
+
+	a, err := New(Config{GridWidth: 5, GridHeight: 5, AllowBlockedEndpoints: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+
+	// the wall forms around the unit
+	for _, node := range []Node{{X: 1, Y: 1}, {X: 1, Y: 2}, {X: 1, Y: 3}, {X: 2, Y: 3}, {X: 3, Y: 3}, {X: 3, Y: 2}, {X: 3, Y: 1}, {X: 2, Y: 2}} {
+		a.AddObstacle(node.X, node.Y)
+	}
+
+	foundPath, err := a.FindPath(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be an escape route", err)
+	}
+	// down through the gap, around the wall and up to the end node
+	if len(foundPath) != 7 || foundPath[1].X != 2 || foundPath[1].Y != 1 {
+		t.Error("path should leave through the gap: ", foundPath)
+	}
+
+	// the end node is entered even if it is blocked
+	a.AddObstacle(endNode.X, endNode.Y)
+	foundPath, err = a.FindPath(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("the blocked end node should be reachable", err)
+	}
+	if last := foundPath[len(foundPath)-1]; last.X != endNode.X || last.Y != endNode.Y {
+		t.Error("path should end at the end node: ", foundPath)
+	}
+
+	// other blocked cells are still avoided
+	for _, node := range foundPath[1 : len(foundPath)-1] {
+		if a.invalidList.Contains(node) {
+			t.Error("path crosses an obstacle: ", node)
+		}
+	}
+
+	// the bounds are still checked
+	if _, err = a.FindPath(nil, startNode, Node{X: 5, Y: 2}); err != ErrOutOfBounds {
+		t.Error("end node should be out of bounds", err)
+	}
+
+	// without the flag the unit is stuck
+	a, err = New(Config{GridWidth: 5, GridHeight: 5, InvalidNodes: []Node{startNode}})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if _, err = a.FindPath(nil, startNode, endNode); err != ErrStartBlocked {
+		t.Error("start node should be blocked", err)
+	}
+}
+
+func TestAstar_FindPathSearchBounds(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [ ]   S: StartNode
+	// [ ] [O] [O] [O] [ ]   E: EndNode
+	// [ ] [S] [ ] [E] [ ]   O: ObstacleNode
+	// [ ] [O] [O] [O] [ ]   the SearchBounds span 1/1 to 3/3
+	// [ ] [ ] [ ] [ ] [ ]
+
+	startNode := Node{X: 1, Y: 2}
+	endNode := Node{X: 3, Y: 2}
+	bounds := Rect{MinX: 1, MinY: 1, MaxX: 3, MaxY: 3}
+
+	obstacleNodes := []Node{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 3, Y: 1}, {X: 1, Y: 3}, {X: 2, Y: 3}, {X: 3, Y: 3}}
+
+	a, err := New(Config{GridWidth: 5, GridHeight: 5, InvalidNodes: obstacleNodes, SearchBounds: &bounds})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	// the finder keeps its own copy
+	bounds.MaxX = 0
+	_, stats, err := a.FindPathStats(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path inside the bounds", err)
+	}
+	if stats.Expanded > 3 {
+		t.Error("search should stay inside the bounds: ", stats.Expanded)
+	}
+
+	// the only route leads around the wall outside of the bounds
+	a.AddObstacle(2, 2)
+	foundPath, err := a.FindPath(nil, startNode, endNode)
+	if err != ErrorNoPath {
+		t.Error("there should be no path inside the bounds", foundPath, err)
+	}
+
+	// an end node outside of the bounds cannot be reached
+	if _, err = a.FindPath(nil, startNode, Node{X: 4, Y: 2}); err != ErrorNoPath {
+		t.Error("end node should be outside the bounds", err)
+	}
+	if _, err = a.FindPath(nil, startNode, Node{X: 5, Y: 2}); err != ErrOutOfBounds {
+		t.Error("end node should be out of bounds", err)
+	}
+}
+
+func TestAstar_FindPathDeterministic(t *testing.T) {
+
+	// an open grid has many paths of the same cost
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 9, Y: 9}
+
+	for _, tieBreaking := range []TieBreaking{TieBreakNone, TieBreakLowerH, TieBreakCrossProduct} {
+		a, err := New(Config{GridWidth: 10, GridHeight: 10, AllowDiagonal: true, TieBreaking: tieBreaking})
+		if err != nil {
+			t.Fatal("there should be no error", err)
+		}
+		wantPath, err := a.FindPath(nil, startNode, endNode)
+		if err != nil {
+			t.Fatal("there should be a path", err)
+		}
+		for i := 0; i < 50; i++ {
+			foundPath, _ := a.FindPath(nil, startNode, endNode)
+			if !reflect.DeepEqual(foundPath, wantPath) {
+				t.Fatal("same query should return the same path: ", tieBreaking, foundPath, wantPath)
+			}
+		}
+	}
+}
+
+func TestAstar_FindPathNoPathDetails(t *testing.T) {
+
+	// [ ] [ ] [O] [ ]   S: StartNode
+	// [ ] [ ] [O] [ ]   E: EndNode
+	// [S] [ ] [O] [E]   O: ObstacleNode
+
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 3, Y: 0}
+	obstacleNodes := []Node{{X: 2, Y: 0}, {X: 2, Y: 1}, {X: 2, Y: 2}}
+
+	a, err := New(Config{GridWidth: 4, GridHeight: 3, InvalidNodes: obstacleNodes, NoPathDetails: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	_, err = a.FindPath(nil, startNode, endNode)
+	if !errors.Is(err, ErrorNoPath) {
+		t.Fatal("there should be no path", err)
+	}
+	var noPathErr *NoPathError
+	if !errors.As(err, &noPathErr) {
+		t.Fatal("error should carry the details", err)
+	}
+	if noPathErr.ExpandedCount != 6 {
+		t.Error("search should expand the left side: ", noPathErr.ExpandedCount)
+	}
+	if noPathErr.ClosestNode.X != 1 || noPathErr.ClosestNode.Y != 0 {
+		t.Error("closest node should be next to the wall: ", noPathErr.ClosestNode)
+	}
+
+	if reachable, err := a.IsReachable(nil, startNode, endNode); reachable || err != nil {
+		t.Error("end node should not be reachable", reachable, err)
+	}
+}
+
+func TestAstar_FindPathGoalRadius(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ]   S: StartNode
+	// [ ] [ ] [ ] [E]   E: EndNode, blocked
+	// [S] [ ] [ ] [ ]
+
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 3, Y: 1}
+
+	a, err := New(Config{GridWidth: 4, GridHeight: 3, InvalidNodes: []Node{endNode}, GoalRadius: 1})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, reachedExact, err := a.FindPathReached(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path next to the end node", err)
+	}
+	last := foundPath[len(foundPath)-1]
+	if reachedExact || ManhattanDistance(last, endNode) != 1 {
+		t.Error("path should end one cell before the end node: ", foundPath, reachedExact)
+	}
+	if len(foundPath) != 4 {
+		t.Error("path should stop at the first node within the radius: ", foundPath)
+	}
+
+	// the variants without support for the radius fall back to FindPath
+	a, err = New(Config{GridWidth: 4, GridHeight: 3, InvalidNodes: []Node{endNode}, AllowDiagonal: true, GoalRadius: 1})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	wantPath, err := a.FindPath(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path next to the end node", err)
+	}
+	variants := map[string]func(ctx IContext, startNode, endNode Node) ([]Node, error){
+		"FindPathJPS":           a.FindPathJPS,
+		"FindPathTheta":         a.FindPathTheta,
+		"FindPathBidirectional": a.FindPathBidirectional,
+	}
+	for name, findPath := range variants {
+		if foundPath, err := findPath(nil, startNode, endNode); err != nil || !samePathNodes(foundPath, wantPath) {
+			t.Error(name+" should stop within the radius like FindPath: ", foundPath, err)
+		}
+	}
+
+	// without the radius the blocked end node is rejected
+	a, err = New(Config{GridWidth: 4, GridHeight: 3, InvalidNodes: []Node{endNode}})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if _, err = a.FindPath(nil, startNode, endNode); err != ErrEndBlocked {
+		t.Error("end node should be blocked", err)
+	}
+}
+
+func TestAstar_FindPathStartIsEnd(t *testing.T) {
+	a, err := New(Config{GridWidth: 4, GridHeight: 4, AllowDiagonal: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	startNode := Node{X: 1, Y: 2}
+	checkPath := func(name string, foundPath []Node, err error) {
+		if err != nil || len(foundPath) != 1 || foundPath[0].X != startNode.X || foundPath[0].Y != startNode.Y {
+			t.Error(name+" should return only the start node: ", foundPath, err)
+		}
+	}
+
+	foundPath, cost, err := a.FindPathWithCost(nil, startNode, startNode)
+	checkPath("FindPathWithCost", foundPath, err)
+	if cost != 0 {
+		t.Error("staying at the start node should cost nothing: ", cost)
+	}
+	foundPath, err = a.FindPath(nil, startNode, startNode)
+	checkPath("FindPath", foundPath, err)
+	foundPath, err = a.FindPathJPS(nil, startNode, startNode)
+	checkPath("FindPathJPS", foundPath, err)
+	foundPath, err = a.FindPathTheta(nil, startNode, startNode)
+	checkPath("FindPathTheta", foundPath, err)
+	foundPath, err = a.FindPathBidirectional(nil, startNode, startNode)
+	checkPath("FindPathBidirectional", foundPath, err)
+
+	if directions, err := a.FindPathDirections(nil, startNode, startNode); err != nil || len(directions) != 0 {
+		t.Error("there should be no move: ", directions, err)
+	}
+
+	a.AddObstacle(startNode.X, startNode.Y)
+	if _, err = a.FindPath(nil, startNode, startNode); err != ErrStartBlocked {
+		t.Error("a blocked start node is checked first: ", err)
+	}
+}
+
+// fireContext blocks the fire cell until the time clears
+type fireContext struct {
+	TestContext
+	fire  Node
+	clear int
+}
+
+func (c *fireContext) IsInBlockAt(x, y, t int) bool {
+	return x == c.fire.X && y == c.fire.Y && t < c.clear
+}
+
+func TestAstar_FindPathTimedBlocks(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [ ]   S: StartNode
+	// [S] [ ] [F] [ ] [E]   E: EndNode
+	// [ ] [ ] [ ] [ ] [ ]   F: fire until the time clear
+
+	startNode := Node{X: 0, Y: 1}
+	endNode := Node{X: 4, Y: 1}
+	a, err := New(Config{GridWidth: 5, GridHeight: 3})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+
+	// the fire is gone when the path arrives at t = 2
+	ctx := &fireContext{TestContext: TestContext{tarX: endNode.X, tarY: endNode.Y}, fire: Node{X: 2, Y: 1}, clear: 2}
+	foundPath, err := a.FindPath(ctx, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if len(foundPath) != 5 {
+		t.Error("the path should go through the cleared fire: ", foundPath)
+	}
+
+	// the fire still burns at t = 2, the path goes around it
+	ctx.clear = 3
+	foundPath, err = a.FindPath(ctx, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	for step, node := range foundPath {
+		if ctx.IsInBlockAt(node.X, node.Y, step) {
+			t.Error("the path should not enter the fire while it burns: ", foundPath)
+		}
+	}
+	if len(foundPath) != 7 {
+		t.Error("the way around the fire should have 7 nodes: ", foundPath)
+	}
+
+	// JPS falls back to FindPath
+	if jpsPath, err := a.FindPathJPS(ctx, startNode, endNode); err != nil || len(jpsPath) != 7 {
+		t.Error("FindPathJPS should avoid the fire as well: ", jpsPath, err)
+	}
+}
+
+// chunkContext streams the cells with -1000 <= x < 1000 and a wall at x = 0 with a gap at y = 5
+type chunkContext struct {
+	TestContext
+}
+
+func (c *chunkContext) IsInBlock(x, y int) bool {
+	return x == 0 && y != 5
+}
+
+func (c *chunkContext) IsInBounds(x, y int) bool {
+	return x >= -1000 && x < 1000 && y >= -1000 && y < 1000
+}
+
+func TestAstar_FindPathUnbounded(t *testing.T) {
+	a, err := New(Config{Unbounded: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+
+	startNode := Node{X: -500, Y: 0}
+	endNode := Node{X: 500, Y: 0}
+	ctx := &chunkContext{TestContext{tarX: endNode.X, tarY: endNode.Y}}
+	foundPath, cost, err := a.FindPathWithCost(ctx, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path through the gap", err)
+	}
+	if cost != 1010 {
+		t.Error("the path should make the way to the gap and back: ", cost)
+	}
+	for _, node := range foundPath {
+		if node.X == 0 && node.Y != 5 {
+			t.Error("the path should pass the wall at the gap: ", node)
+		}
+	}
+
+	if _, err = a.FindPath(ctx, startNode, Node{X: 1000, Y: 0}); err != ErrOutOfBounds {
+		t.Error("the ctx should decide the bounds", err)
+	}
+	if _, err = a.CountShortestPaths(ctx, startNode, endNode); err != ErrUnboundedGrid {
+		t.Error("the cell counts need a bounded grid", err)
+	}
+	if _, err = a.DistanceField(nil, startNode); err != ErrUnboundedGrid {
+		t.Error("the distance field needs a bounded grid", err)
+	}
+	if _, err = a.FlowField(nil, endNode); err != ErrUnboundedGrid {
+		t.Error("the flow field needs a bounded grid", err)
+	}
+	if _, err = New(Config{Unbounded: true, WrapEdges: true}); err == nil {
+		t.Error("an unbounded grid should not wrap")
+	}
+}
+
+func TestAstar_Steps(t *testing.T) {
+
+	// [ ] [ ] [O] [ ]   S: StartNode
+	// [ ] [ ] [O] [ ]   E: EndNode
+	// [S] [ ] [O] [E]   O: ObstacleNode
+
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 3, Y: 0}
+	obstacleNodes := []Node{{X: 2, Y: 0}, {X: 2, Y: 1}, {X: 2, Y: 2}}
+
+	a, err := New(Config{GridWidth: 4, GridHeight: 3, InvalidNodes: obstacleNodes})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if _, err := a.FindPath(nil, startNode, endNode); err != ErrorNoPath {
+		t.Fatal("there should be no path", err)
+	}
+	if a.Steps() != 6 {
+		t.Error("the failed search should expand the left side: ", a.Steps())
+	}
+
+	a.RemoveObstacle(2, 2)
+	_, stats, err := a.FindPathStats(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if a.Steps() != stats.Expanded {
+		t.Error("the steps should be the ones of the last search: ", a.Steps(), stats.Expanded)
+	}
+}
+
+func TestAstar_FindPathMinMoveCost(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ] [E]   S: StartNode
+	// [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ]   E: EndNode
+	// [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ]   O: ObstacleNode
+	// [O] [O] [O] [O] [O] [O] [O] [ ] [ ] [ ]
+	// [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ]
+	// ...                                       every cell is a swamp with a Weighting of 4
+	// [S] [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ]
+
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 9, Y: 9}
+	var obstacleNodes []Node
+	for x := 0; x < 7; x++ {
+		obstacleNodes = append(obstacleNodes, Node{X: x, Y: 6})
+	}
+	swamp := []WeightedRect{{Bounds: Rect{MaxX: 9, MaxY: 9}, Weighting: 4}}
+
+	a, err := New(Config{GridWidth: 10, GridHeight: 10, InvalidNodes: obstacleNodes, WeightedRegions: swamp})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	_, plainStats, err := a.FindPathStats(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	_, plainCost, _ := a.FindPathWithCost(nil, startNode, endNode)
+
+	a, err = New(Config{GridWidth: 10, GridHeight: 10, InvalidNodes: obstacleNodes, WeightedRegions: swamp, MinMoveCost: 5})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	_, stats, err := a.FindPathStats(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	_, cost, _ := a.FindPathWithCost(nil, startNode, endNode)
+	if cost != plainCost {
+		t.Error("the path should stay optimal: ", cost, plainCost)
+	}
+	if stats.Expanded >= plainStats.Expanded {
+		t.Error("the search should expand fewer nodes: ", stats.Expanded, plainStats.Expanded)
+	}
+	if h := a.H(startNode, endNode) * a.config.OrthogonalCost; h != 18 {
+		t.Error("H should stay the unscaled distance: ", h)
+	}
+	if h := a.hCost(startNode, endNode); h != 90 {
+		t.Error("the cost estimate should use the cheapest step: ", h)
+	}
+
+	if _, err := New(Config{GridWidth: 10, GridHeight: 10, MinMoveCost: -1}); err == nil {
+		t.Error("a negative MinMoveCost should be an error")
+	}
+}
+
+func TestAstar_FindPathCorridor(t *testing.T) {
+
+	// [S] [ ] [ ] [O] [ ] [ ] [ ] [ ] [ ] [E]   a 10x1 corridor, O: ObstacleNode
+
+	a, err := New(Config{GridWidth: 10, GridHeight: 1})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, cost, err := a.FindPathWithCost(nil, Node{X: 0, Y: 0}, Node{X: 9, Y: 0})
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if len(foundPath) != 10 || cost != 9 {
+		t.Error("the path should follow the corridor: ", foundPath, cost)
+	}
+	a.AddObstacle(3, 0)
+	if _, err := a.FindPath(nil, Node{X: 0, Y: 0}, Node{X: 9, Y: 0}); err != ErrorNoPath {
+		t.Error("the blocked corridor should have no path: ", err)
+	}
+
+	// the same corridor upright as a 1x10 grid with diagonals
+	a, err = New(Config{GridWidth: 1, GridHeight: 10, AllowDiagonal: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, err = a.FindPath(nil, Node{X: 0, Y: 9}, Node{X: 0, Y: 0})
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if len(foundPath) != 10 || foundPath[5].X != 0 || foundPath[5].Y != 4 {
+		t.Error("the path should follow the corridor: ", foundPath)
+	}
+	if _, err := a.FindPath(nil, Node{X: 0, Y: 0}, Node{X: 1, Y: 0}); err != ErrOutOfBounds {
+		t.Error("a node beside the corridor should be out of bounds: ", err)
+	}
+
+	if _, err := New(Config{GridWidth: 10, GridHeight: 0}); !errors.Is(err, ErrGridHeight) {
+		t.Error("the error should name the empty dimension: ", err)
+	}
+}
@@ -0,0 +1,76 @@
+package astar
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Query is a single search of FindPaths
+type Query struct {
+	StartNode, EndNode Node
+}
+
+// Result is the outcome of a Query, Path and Err are the return values of FindPath
+type Result struct {
+	Path []Node
+	Err  error
+}
+
+// FindPaths runs FindPath for every query and returns the results in the order of the queries
+// the error is the one of the first query without a path, nil if all paths were found
+//
+// The batch locks the obstacles once for all queries and the searches reuse the pooled search states,
+// so many queries against the same grid are cheaper than single FindPath calls.
+// AddObstacle and RemoveObstacle wait until the batch is done
+func (a *PathFinder) FindPaths(ctx IContext, queries []Query) ([]Result, error) {
+	return a.FindPathsParallel(ctx, queries, 1)
+}
+
+// FindPathsParallel works like FindPaths but spreads the queries over workers goroutines,
+// the IContext is shared by them and must be safe for concurrent use
+func (a *PathFinder) FindPathsParallel(ctx IContext, queries []Query, workers int) ([]Result, error) {
+	if workers < 1 {
+		return nil, errors.New("workers must be min 1")
+	}
+
+	a.obstacleMu.RLock()
+	defer a.obstacleMu.RUnlock()
+
+	results := make([]Result, len(queries))
+	search := func(i int) {
+		opts := searchOptions{maxSteps: StepsNoLimit}
+		results[i].Path, results[i].Err = a.findPathLocked(context.Background(), ctx, queries[i].StartNode, queries[i].EndNode, opts)
+	}
+
+	if workers == 1 {
+		for i := range queries {
+			search(i)
+		}
+	} else {
+		// the workers already run under the read lock of the batch
+		indices := make(chan int)
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range indices {
+					search(i)
+				}
+			}()
+		}
+		for i := range queries {
+			indices <- i
+		}
+		close(indices)
+		wg.Wait()
+	}
+
+	for _, result := range results {
+		if result.Err != nil {
+			return results, result.Err
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,57 @@
+package astar
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAstar_FindPaths(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [ ]   O: ObstacleNode
+	// [ ] [O] [O] [O] [ ]
+	// [ ] [ ] [ ] [O] [ ]
+	// [O] [O] [ ] [O] [ ]
+	// [ ] [ ] [ ] [O] [ ]
+
+	obstacleNodes := []Node{{X: 1, Y: 3}, {X: 2, Y: 3}, {X: 3, Y: 3}, {X: 3, Y: 2}, {X: 3, Y: 1}, {X: 3, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}}
+	a, err := New(Config{GridWidth: 5, GridHeight: 5, InvalidNodes: obstacleNodes})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+
+	var queries []Query
+	for x := 0; x < 5; x++ {
+		for y := 0; y < 5; y++ {
+			queries = append(queries, Query{StartNode: Node{X: 0, Y: 0}, EndNode: Node{X: x, Y: y}})
+		}
+	}
+
+	results, err := a.FindPaths(nil, queries)
+	if err != ErrEndBlocked {
+		t.Error("first failed query should be the blocked node 0/1", err)
+	}
+	if len(results) != len(queries) {
+		t.Fatal("there should be a result for every query", len(results))
+	}
+	for i, query := range queries {
+		wantPath, wantErr := a.FindPath(nil, query.StartNode, query.EndNode)
+		if results[i].Err != wantErr || !reflect.DeepEqual(results[i].Path, wantPath) {
+			t.Error("result should match FindPath: ", query, results[i], wantPath, wantErr)
+		}
+	}
+
+	parallelResults, err := a.FindPathsParallel(nil, queries, 4)
+	if err != ErrEndBlocked {
+		t.Error("first failed query should be the blocked node 0/1", err)
+	}
+	if !reflect.DeepEqual(parallelResults, results) {
+		t.Error("parallel results should match the serial ones")
+	}
+
+	if _, err = a.FindPathsParallel(nil, queries, 0); err == nil {
+		t.Error("0 workers should be invalid")
+	}
+	if results, err = a.FindPaths(nil, queries[2:3]); err != nil || len(results[0].Path) == 0 {
+		t.Error("there should be a path", results, err)
+	}
+}
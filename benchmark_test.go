@@ -0,0 +1,386 @@
+package astar
+
+import (
+	"math"
+	"runtime"
+	"testing"
+)
+
+// benchmarkOpenGrid searches from one corner of an empty grid to the other one
+func benchmarkOpenGrid(b *testing.B, size int) {
+	a, err := New(Config{GridWidth: size, GridHeight: size})
+	if err != nil {
+		b.Fatal("there should be no error", err)
+	}
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: size - 1, Y: size - 1}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := a.FindPath(nil, startNode, endNode); err != nil {
+			b.Fatal("there should be a path", err)
+		}
+	}
+}
+
+func BenchmarkFindPathOpenGrid500(b *testing.B) {
+	benchmarkOpenGrid(b, 500)
+}
+
+// openSetNodes returns n nodes of a 500 wide grid with varying F values
+func openSetNodes(n int) []Node {
+	nodes := make([]Node, n)
+	for i := range nodes {
+		nodes[i] = Node{X: i % 500, Y: i / 500, f: (i * 7919) % 1000}
+	}
+	return nodes
+}
+
+// BenchmarkOpenSetList drains the open set with the linear List
+func BenchmarkOpenSetList(b *testing.B) {
+	nodes := openSetNodes(5000)
+	for i := 0; i < b.N; i++ {
+		list := NewList()
+		list.Add(nodes...)
+		for !list.IsEmpty() {
+			node, _ := list.GetMinFNode()
+			list.Remove(node)
+		}
+	}
+}
+
+// BenchmarkOpenSetHeap drains the open set with the binary Heap
+func BenchmarkOpenSetHeap(b *testing.B) {
+	nodes := openSetNodes(5000)
+	for i := 0; i < b.N; i++ {
+		h := NewHeap()
+		h.Add(nodes...)
+		for !h.IsEmpty() {
+			node, _ := h.GetMinFNode()
+			h.Remove(node)
+		}
+	}
+}
+
+// BenchmarkClosedSetList checks the membership of every node with the linear List
+func BenchmarkClosedSetList(b *testing.B) {
+	nodes := openSetNodes(5000)
+	list := NewList()
+	list.Add(nodes...)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, node := range nodes {
+			list.Contains(node)
+		}
+	}
+}
+
+// BenchmarkClosedSetNodeSet checks the membership of every node with the NodeSet
+func BenchmarkClosedSetNodeSet(b *testing.B) {
+	nodes := openSetNodes(5000)
+	set := NewNodeSet()
+	set.Add(nodes...)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, node := range nodes {
+			set.Contains(node)
+		}
+	}
+}
+
+// benchmarkDiagonalGrid searches diagonally across an empty grid with 8-directional movement
+func benchmarkDiagonalGrid(b *testing.B, size int, jps bool) {
+	a, err := New(Config{GridWidth: size, GridHeight: size, AllowDiagonal: true, Heuristic: ChebyshevDistance})
+	if err != nil {
+		b.Fatal("there should be no error", err)
+	}
+	startNode := Node{X: 0, Y: size / 3}
+	endNode := Node{X: size - 1, Y: size - 1}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if jps {
+			_, err = a.FindPathJPS(nil, startNode, endNode)
+		} else {
+			_, err = a.FindPath(nil, startNode, endNode)
+		}
+		if err != nil {
+			b.Fatal("there should be a path", err)
+		}
+	}
+}
+
+func BenchmarkFindPathDiagonalGrid1000(b *testing.B) {
+	benchmarkDiagonalGrid(b, 1000, false)
+}
+
+// BenchmarkFindPathDiagonalGrid100 relaxes many open nodes,
+// the parent records come from the nodePool so the allocations do not grow with the expansions
+func BenchmarkFindPathDiagonalGrid100(b *testing.B) {
+	benchmarkDiagonalGrid(b, 100, false)
+}
+
+func BenchmarkFindPathJPSDiagonalGrid1000(b *testing.B) {
+	benchmarkDiagonalGrid(b, 1000, true)
+}
+
+// BenchmarkFindPathRepeated runs the same search again and again like a game loop,
+// run it with -benchmem -benchtime=1000x to see the allocations per FindPath
+func BenchmarkFindPathRepeated(b *testing.B) {
+	var obstacleNodes []Node
+	for y := 0; y < 40; y++ {
+		obstacleNodes = append(obstacleNodes, Node{X: 25, Y: y})
+	}
+	a, err := New(Config{GridWidth: 50, GridHeight: 50, InvalidNodes: obstacleNodes})
+	if err != nil {
+		b.Fatal("there should be no error", err)
+	}
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 49, Y: 0}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := a.FindPath(nil, startNode, endNode); err != nil {
+			b.Fatal("there should be a path", err)
+		}
+	}
+}
+
+func BenchmarkFindPaths(b *testing.B) {
+	a, err := New(Config{GridWidth: 50, GridHeight: 50})
+	if err != nil {
+		b.Fatal("there should be no error", err)
+	}
+	queries := make([]Query, 100)
+	for i := range queries {
+		queries[i] = Query{StartNode: Node{X: i % 50, Y: 0}, EndNode: Node{X: 49 - i%50, Y: 49}}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := a.FindPathsParallel(nil, queries, 4); err != nil {
+			b.Fatal("there should be a path for every query", err)
+		}
+	}
+}
+
+func benchmarkDistanceField(b *testing.B, workers int) {
+	var obstacleNodes []Node
+	for y := 0; y < 900; y++ {
+		obstacleNodes = append(obstacleNodes, Node{X: 500, Y: y})
+	}
+	a, err := New(Config{GridWidth: 1000, GridHeight: 1000, InvalidNodes: obstacleNodes, AllowDiagonal: true})
+	if err != nil {
+		b.Fatal("there should be no error", err)
+	}
+	startNode := Node{X: 0, Y: 0}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if workers == 0 {
+			_, err = a.DistanceField(nil, startNode)
+		} else {
+			_, err = a.DistanceFieldParallel(nil, startNode, workers)
+		}
+		if err != nil {
+			b.Fatal("there should be no error", err)
+		}
+	}
+}
+
+func BenchmarkDistanceField1000(b *testing.B) {
+	benchmarkDistanceField(b, 0)
+}
+
+func BenchmarkDistanceFieldParallel1000(b *testing.B) {
+	benchmarkDistanceField(b, runtime.NumCPU())
+}
+
+// benchmarkOpenList searches around a wall, weighted adds mud cells with small Weightings
+func benchmarkOpenList(b *testing.B, buckets, weighted bool) {
+	var obstacleNodes, weightedNodes []Node
+	for y := 0; y < 180; y++ {
+		obstacleNodes = append(obstacleNodes, Node{X: 100, Y: y})
+	}
+	if weighted {
+		for x := 0; x < 200; x += 3 {
+			for y := 0; y < 200; y += 2 {
+				weightedNodes = append(weightedNodes, Node{X: x, Y: y, Weighting: 1 + (x+y)%5})
+			}
+		}
+	}
+	a, err := New(Config{GridWidth: 200, GridHeight: 200, InvalidNodes: obstacleNodes, WeightedNodes: weightedNodes, BucketOpenList: buckets})
+	if err != nil {
+		b.Fatal("there should be no error", err)
+	}
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 199, Y: 0}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := a.FindPath(nil, startNode, endNode); err != nil {
+			b.Fatal("there should be a path", err)
+		}
+	}
+}
+
+func BenchmarkFindPathHeapUniform(b *testing.B) {
+	benchmarkOpenList(b, false, false)
+}
+
+func BenchmarkFindPathBucketsUniform(b *testing.B) {
+	benchmarkOpenList(b, true, false)
+}
+
+func BenchmarkFindPathHeapWeighted(b *testing.B) {
+	benchmarkOpenList(b, false, true)
+}
+
+func BenchmarkFindPathBucketsWeighted(b *testing.B) {
+	benchmarkOpenList(b, true, true)
+}
+
+// benchmarkManyUnitsOneGoal sends 50 units around a wall to the same goal with a
+// landmark heuristic, goalSearcher reuses the cached H of the earlier searches
+func benchmarkManyUnitsOneGoal(b *testing.B, goalSearcher bool) {
+	var obstacleNodes, landmarks []Node
+	for y := 0; y < 45; y++ {
+		obstacleNodes = append(obstacleNodes, Node{X: 25, Y: y})
+	}
+	for i := 0; i < 16; i++ {
+		landmarks = append(landmarks, Node{X: i * 3, Y: 49 - i*3})
+	}
+	a, err := New(Config{GridWidth: 50, GridHeight: 50, InvalidNodes: obstacleNodes, Heuristic: func(nodeA, nodeB Node) int {
+		// the triangle inequality keeps the difference of the landmark distances admissible
+		h := 0
+		for _, landmark := range landmarks {
+			d := int(math.Abs(float64(ManhattanDistance(landmark, nodeA) - ManhattanDistance(landmark, nodeB))))
+			if d > h {
+				h = d
+			}
+		}
+		return h
+	}})
+	if err != nil {
+		b.Fatal("there should be no error", err)
+	}
+	goalNode := Node{X: 49, Y: 0}
+	searcher := a.WithGoal(goalNode)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for unit := 0; unit < 50; unit++ {
+			startNode := Node{X: unit % 10, Y: unit / 10 * 10}
+			if goalSearcher {
+				_, err = searcher.FindPath(nil, startNode)
+			} else {
+				_, err = a.FindPath(nil, startNode, goalNode)
+			}
+			if err != nil {
+				b.Fatal("there should be a path", err)
+			}
+		}
+	}
+}
+
+func BenchmarkFindPathManyUnitsOneGoal(b *testing.B) {
+	benchmarkManyUnitsOneGoal(b, false)
+}
+
+func BenchmarkGoalSearcherManyUnitsOneGoal(b *testing.B) {
+	benchmarkManyUnitsOneGoal(b, true)
+}
+
+// benchmarkNeighbors asks for the eight neighbors of every cell of a small grid,
+// append reuses one buffer instead of a new slice per call
+func benchmarkNeighbors(b *testing.B, appendNodes bool) {
+	a, err := New(Config{GridWidth: 20, GridHeight: 20, AllowDiagonal: true})
+	if err != nil {
+		b.Fatal("there should be no error", err)
+	}
+	var neighbors []Node
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		node := Node{X: i % 20, Y: i / 20 % 20}
+		if appendNodes {
+			neighbors = a.AppendNeighborNodes(nil, neighbors[:0], node)
+		} else {
+			neighbors = a.GetNeighborNodes(nil, node)
+		}
+	}
+}
+
+func BenchmarkGetNeighborNodes(b *testing.B) {
+	benchmarkNeighbors(b, false)
+}
+
+func BenchmarkAppendNeighborNodes(b *testing.B) {
+	benchmarkNeighbors(b, true)
+}
+
+// BenchmarkFindPathManyWeightedNodes searches a grid with 10k WeightedNodes,
+// their Weightings are looked up by the cell instead of walking the whole slice
+func BenchmarkFindPathManyWeightedNodes(b *testing.B) {
+	var weightedNodes []Node
+	for x := 0; x < 200; x += 2 {
+		for y := 0; y < 200; y += 2 {
+			weightedNodes = append(weightedNodes, Node{X: x, Y: y, Weighting: 1 + (x+y)%3})
+		}
+	}
+	a, err := New(Config{GridWidth: 200, GridHeight: 200, WeightedNodes: weightedNodes})
+	if err != nil {
+		b.Fatal("there should be no error", err)
+	}
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 199, Y: 199}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := a.FindPath(nil, startNode, endNode); err != nil {
+			b.Fatal("there should be a path", err)
+		}
+	}
+}
+
+// benchmarkWall runs the first search of a new PathFinder around a wall on a 200x200 grid,
+// so the bytes per search are the memory of FindPath or FindPathFringe without the pooled storage
+func benchmarkWall(b *testing.B, fringe bool) {
+	var obstacleNodes []Node
+	for y := 0; y < 180; y++ {
+		obstacleNodes = append(obstacleNodes, Node{X: 100, Y: y})
+	}
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 199, Y: 0}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		a, err := New(Config{GridWidth: 200, GridHeight: 200, InvalidNodes: obstacleNodes})
+		if err != nil {
+			b.Fatal("there should be no error", err)
+		}
+		if fringe {
+			_, err = a.FindPathFringe(nil, startNode, endNode)
+		} else {
+			_, err = a.FindPath(nil, startNode, endNode)
+		}
+		if err != nil {
+			b.Fatal("there should be a path", err)
+		}
+	}
+}
+
+func BenchmarkFindPathWall(b *testing.B) {
+	benchmarkWall(b, false)
+}
+
+func BenchmarkFindPathFringeWall(b *testing.B) {
+	benchmarkWall(b, true)
+}
@@ -0,0 +1,126 @@
+package astar
+
+// frontier is the search state of one direction of the bidirectional search
+type frontier struct {
+	openList   Heap
+	closedList NodeSet
+	target     Node // 这一侧搜索的目标点
+	backward   bool // 从终点向起点搜索
+}
+
+// bestG returns the best known G of the node in this direction
+func (f *frontier) bestG(node Node) (Node, bool) {
+	if closedNode, ok := f.closedList.Get(node); ok {
+		return closedNode, true
+	}
+	return f.openList.Get(node)
+}
+
+// minF returns the smallest F of the open list
+func (f *frontier) minF() int {
+	node, err := f.openList.GetMinFNode()
+	if err != nil {
+		return infCost
+	}
+	return node.f
+}
+
+// FindPathBidirectional searches from the start and the end node at the same time
+// and stitches both half paths where the frontiers meet
+// The return value has the same format as FindPath
+//
+// The search stops once the smallest F of one open list is not lower than the
+// cheapest meeting point found so far, so with an admissible heuristic
+// the path is as short as the one of FindPath.
+// ctx.IsNearEnough is not used, the backward search starts at the exact end node
+// With Config.Neighbors set it falls back to FindPath, the custom neighbors may be one-way,
+// with Config.AllowBlockedEndpoints, Config.TurnPenalty, an ITimedContext
+// and Config.GoalRadius without an IContext as well
+func (a *PathFinder) FindPathBidirectional(ctx IContext, startNode, endNode Node) ([]Node, error) {
+	if a.config.Neighbors != nil || a.config.AllowBlockedEndpoints || a.config.TurnPenalty > 0 || isTimed(ctx) || a.usesGoalRadius(ctx) {
+		return a.FindPath(ctx, startNode, endNode)
+	}
+
+	a.obstacleMu.RLock()
+	defer a.obstacleMu.RUnlock()
+	endNode = a.snapEnd(ctx, endNode)
+
+	if err := a.checkEndpoints(ctx, startNode, endNode, false); err != nil {
+		return nil, err
+	}
+	if startNode.X == endNode.X && startNode.Y == endNode.Y {
+		return []Node{startNode}, nil
+	}
+
+	forward := &frontier{target: endNode}
+	backward := &frontier{target: startNode, backward: true}
+	forward.openList.Add(startNode)
+	backward.openList.Add(endNode)
+
+	bestCost := infCost
+	var meetForward, meetBackward Node
+
+	for !forward.openList.IsEmpty() && !backward.openList.IsEmpty() {
+		if forward.minF() >= bestCost || backward.minF() >= bestCost {
+			break
+		}
+
+		// expand the smaller frontier
+		current, other := forward, backward
+		if backward.openList.Len() < forward.openList.Len() {
+			current, other = backward, forward
+		}
+
+		currentNode, _ := current.openList.GetMinFNode()
+		current.openList.Remove(currentNode)
+		current.closedList.Add(currentNode)
+
+		for _, neighbor := range a.appendNeighborNodes(ctx, nil, &currentNode, nil) {
+			if current.closedList.Contains(neighbor) {
+				continue
+			}
+
+			if current.backward {
+				neighbor.g = addCost(currentNode.g, a.stepCost(neighbor, currentNode))
+			} else {
+				neighbor.g = addCost(currentNode.g, a.stepCost(currentNode, neighbor))
+			}
+			if neighbor.g >= infCost {
+				continue
+			}
+			neighbor.h = a.hCost(neighbor, current.target)
+			neighbor.f = neighbor.g + neighbor.h
+
+			openNode, ok := current.openList.Get(neighbor)
+			if !ok {
+				current.openList.Add(neighbor)
+			} else if neighbor.g < openNode.g {
+				current.openList.Update(neighbor)
+			}
+
+			// the frontiers touch
+			if otherNode, ok := other.bestG(neighbor); ok && addCost(neighbor.g, otherNode.g) < bestCost {
+				bestCost = addCost(neighbor.g, otherNode.g)
+				meetForward, meetBackward = neighbor, otherNode
+				if current.backward {
+					meetForward, meetBackward = otherNode, neighbor
+				}
+			}
+		}
+	}
+
+	if bestCost == infCost {
+		return nil, ErrorNoPath
+	}
+
+	// continue the forward chain with the backward half
+	currentNode := meetForward
+	for backwardNode := meetBackward.parent; backwardNode != nil; backwardNode = backwardNode.parent {
+		parentNode := currentNode
+		nextNode := Node{X: backwardNode.X, Y: backwardNode.Y, parent: &parentNode}
+		nextNode.g = parentNode.g + a.stepCost(parentNode, nextNode)
+		nextNode.f = nextNode.g
+		currentNode = nextNode
+	}
+	return a.getNodePath(currentNode), nil
+}
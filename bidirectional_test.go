@@ -0,0 +1,88 @@
+package astar
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestAstar_FindPathBidirectional(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [ ]   S: StartNode
+	// [ ] [O] [ ] [E] [ ]   E: EndNode
+	// [ ] [ ] [O] [P] [ ]   O: ObstacleNode
+	// [ ] [S] [P] [P] [ ]   P: Valid Path
+	// [ ] [ ] [ ] [ ] [ ]
+
+	startNode := Node{X: 1, Y: 1}
+	endNode := Node{X: 3, Y: 3}
+	obstacleNodes := []Node{
+		{X: 1, Y: 3},
+		{X: 2, Y: 2},
+	}
+
+	// setup a 5x5 grid
+	a, err := New(Config{GridWidth: 5, GridHeight: 5, InvalidNodes: obstacleNodes})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, err := a.FindPathBidirectional(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if len(foundPath) != 5 || foundPath[len(foundPath)-1].g != 4 {
+		t.Error("path should have 5 nodes: ", foundPath)
+	}
+	if foundPath[len(foundPath)-1].X != endNode.X || foundPath[len(foundPath)-1].Y != endNode.Y {
+		t.Error("end node should be the last node: ", foundPath)
+	}
+	checkPathConnected(t, a, startNode, foundPath)
+
+	// start equals end
+	foundPath, err = a.FindPathBidirectional(nil, startNode, startNode)
+	if err != nil || len(foundPath) != 1 {
+		t.Error("path should only have the start node: ", foundPath, err)
+	}
+
+	// no path
+	a, err = New(Config{GridWidth: 5, GridHeight: 5, InvalidNodes: []Node{{X: 0, Y: 1}, {X: 1, Y: 0}}})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if _, err = a.FindPathBidirectional(nil, Node{X: 0, Y: 0}, endNode); err != ErrorNoPath {
+		t.Error("there should be no path", err)
+	}
+}
+
+func TestAstar_FindPathBidirectionalOptimal(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 19, Y: 19}
+
+	for i := 0; i < 50; i++ {
+		config := Config{GridWidth: 20, GridHeight: 20, AllowDiagonal: i%2 == 1, Heuristic: ChebyshevDistance}
+		for k := 0; k < 100; k++ {
+			node := Node{X: r.Intn(20), Y: r.Intn(20)}
+			if (node.X == 0 && node.Y == 0) || (node.X == 19 && node.Y == 19) {
+				continue
+			}
+			config.InvalidNodes = append(config.InvalidNodes, node)
+		}
+		for k := 0; k < 30; k++ {
+			config.WeightedNodes = append(config.WeightedNodes, Node{X: r.Intn(20), Y: r.Intn(20), Weighting: r.Intn(10)})
+		}
+		config.WeightedNodes = withoutInvalidNodes(config.WeightedNodes, config.InvalidNodes)
+
+		a, err := New(config)
+		if err != nil {
+			t.Fatal("there should be no error", err)
+		}
+		_, wantCost, wantErr := a.FindPathWithCost(nil, startNode, endNode)
+		foundPath, err := a.FindPathBidirectional(nil, startNode, endNode)
+		if (err == nil) != (wantErr == nil) {
+			t.Fatal("bidirectional and FindPath disagree", err, wantErr)
+		}
+		if err == nil && foundPath[len(foundPath)-1].g != wantCost {
+			t.Error("bidirectional cost should be optimal", foundPath[len(foundPath)-1].g, wantCost)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package astar
+
+// openSet is the open list of a search, a Heap or a bucketQueue
+type openSet interface {
+	push(node Node)
+	get(node Node) (Node, bool)
+	update(node Node)
+	popMin() (Node, error)
+	size() int
+	all() []Node
+	clear()
+}
+
+// maxBucketRange is the number of F values a bucketQueue keeps in buckets,
+// nodes with an F beyond the window wait in an overflow heap
+const maxBucketRange = 1 << 12
+
+// bucketQueue is an open list with one bucket for every F value (Dial's algorithm)
+// the nodes of a bucket are taken in LIFO order
+//
+// adding and taking a node is O(1) as long as the F values of the open nodes stay within
+// maxBucketRange of each other, which holds for the small integer step costs of most grids.
+// The window of buckets moves up with the smallest F, so a long flood keeps using them
+type bucketQueue struct {
+	buckets   [][]Node          // 下标为F - base
+	base      int               // 第一个桶的F
+	min       int               // 可能非空的最小桶
+	count     int               // 桶中的节点数
+	positions map[[2]int][2]int // 节点的F和在桶中的下标
+	overflow  Heap              // F超出桶范围的节点
+}
+
+func (q *bucketQueue) push(node Node) {
+	if q.positions == nil {
+		q.positions = make(map[[2]int][2]int)
+	}
+	q.remove(node)
+
+	if q.count == 0 {
+		q.base, q.min = node.f, 0
+	}
+	bucket := node.f - q.base
+	if bucket < 0 && len(q.buckets)-bucket <= maxBucketRange {
+		// an inconsistent heuristic lowered F, move the buckets up
+		q.buckets = append(make([][]Node, -bucket), q.buckets...)
+		q.base, q.min, bucket = node.f, 0, 0
+	}
+	if bucket < 0 || bucket >= maxBucketRange {
+		q.overflow.Add(node)
+		return
+	}
+
+	for bucket >= len(q.buckets) {
+		q.buckets = append(q.buckets, nil)
+	}
+	q.positions[nodeKey(node)] = [2]int{node.f, len(q.buckets[bucket])}
+	q.buckets[bucket] = append(q.buckets[bucket], node)
+	q.count++
+	if bucket < q.min {
+		q.min = bucket
+	}
+}
+
+func (q *bucketQueue) get(node Node) (Node, bool) {
+	if position, ok := q.positions[nodeKey(node)]; ok {
+		return q.buckets[position[0]-q.base][position[1]], true
+	}
+	return q.overflow.Get(node)
+}
+
+func (q *bucketQueue) update(node Node) {
+	if _, ok := q.get(node); ok {
+		q.push(node)
+	}
+}
+
+func (q *bucketQueue) popMin() (Node, error) {
+	for q.count > 0 && len(q.buckets[q.min]) == 0 {
+		q.min++
+	}
+	if q.min >= maxBucketRange/2 {
+		q.shift()
+	}
+
+	overflowNode, err := q.overflow.GetMinFNode()
+	if q.count == 0 {
+		if err == nil {
+			q.overflow.Remove(overflowNode)
+		}
+		return overflowNode, err
+	}
+
+	bucket := q.buckets[q.min]
+	node := bucket[len(bucket)-1]
+	if err == nil && overflowNode.f < node.f {
+		q.overflow.Remove(overflowNode)
+		return overflowNode, nil
+	}
+	q.remove(node)
+	return node, nil
+}
+
+// shift moves the empty buckets below min behind the others and raises the base by min,
+// so the buckets cover the F values from the smallest one on again.
+// The F of the positions is absolute, they stay valid
+func (q *bucketQueue) shift() {
+	reverseBuckets(q.buckets[:q.min])
+	reverseBuckets(q.buckets[q.min:])
+	reverseBuckets(q.buckets)
+	q.base += q.min
+	q.min = 0
+}
+
+// reverseBuckets reverses the order of the buckets in place
+func reverseBuckets(buckets [][]Node) {
+	for i, j := 0, len(buckets)-1; i < j; i, j = i+1, j-1 {
+		buckets[i], buckets[j] = buckets[j], buckets[i]
+	}
+}
+
+// remove takes the node with the coordinates of node out of the queue
+func (q *bucketQueue) remove(node Node) {
+	key := nodeKey(node)
+	position, ok := q.positions[key]
+	if !ok {
+		q.overflow.Remove(node)
+		return
+	}
+
+	// the last node of the bucket takes the free place
+	bucket := q.buckets[position[0]-q.base]
+	last := len(bucket) - 1
+	if position[1] != last {
+		bucket[position[1]] = bucket[last]
+		q.positions[nodeKey(bucket[last])] = position
+	}
+	q.buckets[position[0]-q.base] = bucket[:last]
+	delete(q.positions, key)
+	q.count--
+}
+
+func (q *bucketQueue) size() int {
+	return q.count + q.overflow.Len()
+}
+
+// all returns the nodes of the buckets and of the overflow heap in no particular order
+func (q *bucketQueue) all() []Node {
+	nodes := make([]Node, 0, q.size())
+	for _, bucket := range q.buckets {
+		nodes = append(nodes, bucket...)
+	}
+	return append(nodes, q.overflow.All()...)
+}
+
+// clear empties the buckets and keeps their storage
+func (q *bucketQueue) clear() {
+	for i := range q.buckets {
+		q.buckets[i] = q.buckets[i][:0]
+	}
+	for key := range q.positions {
+		delete(q.positions, key)
+	}
+	q.count, q.min = 0, 0
+	q.overflow.Clear()
+}
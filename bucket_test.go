@@ -0,0 +1,98 @@
+package astar
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestBucketQueue(t *testing.T) {
+	var q bucketQueue
+
+	q.push(Node{X: 0, Y: 0, f: 5})
+	q.push(Node{X: 1, Y: 0, f: 3})
+	q.push(Node{X: 2, Y: 0, f: 5})
+	q.push(Node{X: 3, Y: 0, f: 3 + maxBucketRange})
+	if q.size() != 4 {
+		t.Error("queue should have 4 nodes: ", q.size())
+	}
+
+	// a lower F moves the node to another bucket
+	q.update(Node{X: 0, Y: 0, f: 4})
+	q.update(Node{X: 9, Y: 9, f: 1})
+	if node, ok := q.get(Node{X: 0, Y: 0}); !ok || node.f != 4 {
+		t.Error("node should be updated: ", node, ok)
+	}
+	if _, ok := q.get(Node{X: 9, Y: 9}); ok {
+		t.Error("update should not add a node")
+	}
+
+	// F below the first bucket
+	q.push(Node{X: 4, Y: 0, f: 2})
+
+	for _, want := range []Node{{X: 4, f: 2}, {X: 1, f: 3}, {X: 0, f: 4}, {X: 2, f: 5}, {X: 3, f: 3 + maxBucketRange}} {
+		node, err := q.popMin()
+		if err != nil || node.X != want.X || node.f != want.f {
+			t.Error("wrong node order: ", node, want, err)
+		}
+	}
+	if _, err := q.popMin(); err != ErrEmptyList || q.size() != 0 {
+		t.Error("queue should be empty", err)
+	}
+
+	// a long flood moves the buckets up instead of filling the overflow heap
+	q.push(Node{X: 0, Y: 0, f: 0})
+	for i := 1; i <= 3*maxBucketRange; i++ {
+		q.push(Node{X: i, Y: 0, f: i})
+		if node, err := q.popMin(); err != nil || node.f != i-1 {
+			t.Fatal("wrong node order: ", node, i-1, err)
+		}
+		if q.overflow.Len() != 0 {
+			t.Fatal("the buckets should follow the smallest F: ", i, q.overflow.Len())
+		}
+	}
+	q.clear()
+
+	q.push(Node{X: 1, Y: 1, f: 7})
+	q.clear()
+	if q.size() != 0 {
+		t.Error("clear should empty the queue")
+	}
+}
+
+func TestAstar_FindPathBucketOpenList(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	for i := 0; i < 20; i++ {
+		var obstacleNodes, weightedNodes []Node
+		for n := 0; n < 120; n++ {
+			node := Node{X: r.Intn(20), Y: r.Intn(20)}
+			if n%3 == 0 {
+				node.Weighting = r.Intn(30)
+				weightedNodes = append(weightedNodes, node)
+			} else if node.X+node.Y > 0 && node.X+node.Y < 38 {
+				obstacleNodes = append(obstacleNodes, node)
+			}
+		}
+		weightedNodes = withoutInvalidNodes(weightedNodes, obstacleNodes)
+		config := Config{GridWidth: 20, GridHeight: 20, InvalidNodes: obstacleNodes, WeightedNodes: weightedNodes, AllowDiagonal: i%2 == 0}
+
+		a, err := New(config)
+		if err != nil {
+			t.Fatal("there should be no error", err)
+		}
+		config.BucketOpenList = true
+		b, err := New(config)
+		if err != nil {
+			t.Fatal("there should be no error", err)
+		}
+
+		startNode, endNode := Node{X: 0, Y: 0}, Node{X: 19, Y: 19}
+		_, wantCost, wantErr := a.FindPathWithCost(nil, startNode, endNode)
+		foundPath, cost, err := b.FindPathWithCost(nil, startNode, endNode)
+		if err != wantErr || cost != wantCost {
+			t.Error("bucket open list should find a path of the same cost: ", cost, wantCost, err, wantErr)
+		}
+		if err == nil && (foundPath[0] != startNode || foundPath[len(foundPath)-1].X != endNode.X) {
+			t.Error("path should run from start to end: ", foundPath)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package astar
+
+import "math"
+
+// cellCosts are the step costs of cells which are not square
+type cellCosts struct {
+	x, y, diagonal int // 沿X, 沿Y和对角线一步的代价
+	allowDiagonal  bool
+}
+
+// initCellCosts scales the step costs by Config.CellWidth and Config.CellHeight
+// internal function
+func (a *PathFinder) initCellCosts() {
+	a.cellCosts = nil
+	if a.config.CellWidth == 0 && a.config.CellHeight == 0 {
+		return
+	}
+
+	width, height := a.config.CellWidth, a.config.CellHeight
+	if width == 0 {
+		width = 1
+	}
+	if height == 0 {
+		height = 1
+	}
+	costs := &cellCosts{
+		x:             scaleCost(a.config.OrthogonalCost, width),
+		y:             scaleCost(a.config.OrthogonalCost, height),
+		diagonal:      scaleCost(a.config.DiagonalCost, math.Hypot(width, height)/math.Sqrt2),
+		allowDiagonal: a.config.AllowDiagonal,
+	}
+	// the diagonal costs at least the longer and at most both orthogonal steps,
+	// so the cellDistance stays the cost of the cheapest free path
+	if costs.diagonal < costs.x {
+		costs.diagonal = costs.x
+	}
+	if costs.diagonal < costs.y {
+		costs.diagonal = costs.y
+	}
+	if costs.diagonal > costs.x+costs.y {
+		costs.diagonal = costs.x + costs.y
+	}
+	a.cellCosts = costs
+}
+
+// scaleCost returns cost times scale rounded, a step costs at least 1
+func scaleCost(cost int, scale float64) int {
+	scaled := int(math.Round(float64(cost) * scale))
+	if scaled < 1 {
+		return 1
+	}
+	return scaled
+}
+
+// moveCost returns the cost of the step between two neighbors
+func (c *cellCosts) moveCost(from, to Node) int {
+	switch {
+	case from.X != to.X && from.Y != to.Y && c.allowDiagonal:
+		return c.diagonal
+	case from.X != to.X:
+		return c.x
+	}
+	return c.y
+}
+
+// cellDistance returns the cost of the cheapest path between the nodes on a free grid
+// it is the default heuristic with CellWidth and CellHeight
+func (a *PathFinder) cellDistance(nodeA, nodeB Node) int {
+	c := a.cellCosts
+	absX := absInt(nodeA.X - nodeB.X)
+	absY := absInt(nodeA.Y - nodeB.Y)
+	if !c.allowDiagonal {
+		return absX*c.x + absY*c.y
+	}
+
+	diagonals := absX
+	if absY < diagonals {
+		diagonals = absY
+	}
+	return (absX-diagonals)*c.x + (absY-diagonals)*c.y + diagonals*c.diagonal
+}
@@ -0,0 +1,90 @@
+package astar
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestAstar_FindPathCellSize(t *testing.T) {
+
+	// [P] [P] [P] [ ] [ ] [ ] [ ]   S: StartNode
+	// [P] [O] [P] [P] [E] [ ] [ ]   E: EndNode
+	// [P] [ ] [O] [ ] [ ] [ ] [ ]   O: ObstacleNode
+	// [P] [ ] [ ] [O] [ ] [ ] [ ]   P: Path on square cells
+	// [P] [ ] [ ] [ ] [O] [O] [ ]
+	// [S] [ ] [ ] [ ] [ ] [ ] [ ]
+
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 4, Y: 4}
+	config := Config{
+		GridWidth:      7,
+		GridHeight:     6,
+		InvalidNodes:   []Node{{X: 1, Y: 4}, {X: 2, Y: 3}, {X: 3, Y: 2}, {X: 4, Y: 1}, {X: 5, Y: 1}},
+		OrthogonalCost: 10,
+	}
+
+	a, err := New(config)
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	squarePath, err := a.FindPath(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+
+	// on tall cells the long way around the right end avoids the vertical steps
+	config.CellHeight = 3
+	a, err = New(config)
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	tallPath, cost, err := a.FindPathWithCost(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if len(squarePath) != 11 || len(tallPath) != 13 || tallPath[6].X != 6 {
+		t.Error("tall cells should take the right detour: ", squarePath, tallPath)
+	}
+	// 8 steps along X and 4 along Y
+	if cost != 200 {
+		t.Error("cost should be 200: ", cost)
+	}
+}
+
+func TestAstar_FindPathCellSizeOptimal(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	for i := 0; i < 20; i++ {
+		var obstacleNodes []Node
+		for n := 0; n < 80; n++ {
+			if node := (Node{X: r.Intn(20), Y: r.Intn(20)}); node.X+node.Y > 0 && node.X+node.Y < 38 {
+				obstacleNodes = append(obstacleNodes, node)
+			}
+		}
+		config := Config{GridWidth: 20, GridHeight: 20, InvalidNodes: obstacleNodes, AllowDiagonal: i%2 == 0, CellWidth: 2, CellHeight: 0.5}
+
+		// dijkstra is always optimal
+		config.DisableHeuristic = true
+		a, err := New(config)
+		if err != nil {
+			t.Fatal("there should be no error", err)
+		}
+		_, wantCost, wantErr := a.FindPathWithCost(nil, Node{X: 0, Y: 0}, Node{X: 19, Y: 19})
+
+		config.DisableHeuristic = false
+		a, err = New(config)
+		if err != nil {
+			t.Fatal("there should be no error", err)
+		}
+		_, cost, err := a.FindPathWithCost(nil, Node{X: 0, Y: 0}, Node{X: 19, Y: 19})
+		if err != wantErr || cost != wantCost {
+			t.Error("scaled heuristic should find the optimal path: ", cost, wantCost, err)
+		}
+	}
+
+	if _, err := New(Config{GridWidth: 5, GridHeight: 5, CellWidth: -1}); err == nil {
+		t.Error("negative CellWidth should be invalid")
+	}
+	if _, err := New(Config{GridWidth: 5, GridHeight: 5, GridType: GridHex, CellHeight: 2}); err == nil {
+		t.Error("hex cells should not be scaled")
+	}
+}
@@ -0,0 +1,130 @@
+package astar
+
+// Clearance returns the distance from the cell to the nearest obstacle, the InvalidNodes
+// and the added obstacles, or to the nearest cell outside of the grid without WrapEdges.
+// The distance is the Chebyshev distance, on hex grids the HexDistance, so a blocked cell
+// has the clearance 0 and a unit with the Config.AgentRadius r fits on cells above r.
+// It returns 0 for a cell out of bounds and -1 on an Unbounded grid
+//
+// The clearance is computed once by a breadth first search and cached
+// until AddObstacle or RemoveObstacle change the grid
+func (a *PathFinder) Clearance(x, y int) int {
+	if a.config.Unbounded {
+		return -1
+	}
+	a.obstacleMu.RLock()
+	defer a.obstacleMu.RUnlock()
+	index, ok := a.cellIndex(x, y)
+	if !ok {
+		return 0
+	}
+	return a.clearanceMap()[index]
+}
+
+// hasClearance checks if a unit with the AgentRadius fits on the cell,
+// the cells blocked by ctx.IsInBlock are searched within the footprint
+func (a *PathFinder) hasClearance(ctx IContext, x, y int) bool {
+	index, ok := a.cellIndex(x, y)
+	if !ok || a.clearanceMap()[index] <= a.config.AgentRadius {
+		return false
+	}
+	if ctx == nil {
+		return true
+	}
+
+	radius := a.config.AgentRadius
+	for dy := -radius; dy <= radius; dy++ {
+		for dx := -radius; dx <= radius; dx++ {
+			if a.config.GridType == GridHex && HexDistance(Node{}, Node{X: dx, Y: dy}) > radius {
+				continue
+			}
+			cellX, cellY := x+dx, y+dy
+			if a.config.WrapEdges {
+				cellX, cellY = a.wrapXY(cellX, cellY)
+			}
+			if ctx.IsInBlock(cellX, cellY) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// clearanceMap returns the clearance of every cell in the order of cellIndex,
+// it is built on the first call after the obstacles changed
+func (a *PathFinder) clearanceMap() []int {
+	a.clearanceMu.Lock()
+	defer a.clearanceMu.Unlock()
+	if a.clearance == nil {
+		a.clearance = a.buildClearance()
+	}
+	return a.clearance
+}
+
+// invalidateClearance drops the cached clearance, the caller holds the write lock of obstacleMu
+func (a *PathFinder) invalidateClearance() {
+	a.clearanceMu.Lock()
+	a.clearance = nil
+	a.clearanceMu.Unlock()
+}
+
+// buildClearance runs a breadth first search from all obstacles at once,
+// the number of rings to a cell is its distance to the nearest obstacle
+func (a *PathFinder) buildClearance() []int {
+	width, height := a.config.GridWidth, a.config.GridHeight
+	offsets := hexOffsets[:]
+	if a.config.GridType != GridHex {
+		offsets = append(orthogonalOffsets[:len(orthogonalOffsets):len(orthogonalOffsets)], diagonalOffsets[:]...)
+	}
+
+	// without WrapEdges a ring of blocked cells lies around the grid, the unit must fit on it
+	pad := 1
+	if a.config.WrapEdges {
+		pad = 0
+	}
+	outerWidth, outerHeight := width+2*pad, height+2*pad
+	rings := make([]int, outerWidth*outerHeight)
+	var queue []int
+	for outerY := 0; outerY < outerHeight; outerY++ {
+		for outerX := 0; outerX < outerWidth; outerX++ {
+			gridX, gridY := outerX-pad, outerY-pad
+			index := outerY*outerWidth + outerX
+			rings[index] = -1
+			if gridX < 0 || gridY < 0 || gridX >= width || gridY >= height ||
+				a.invalidList.Contains(Node{X: gridX + a.config.OriginX, Y: gridY + a.config.OriginY}) {
+				rings[index] = 0
+				queue = append(queue, index)
+			}
+		}
+	}
+
+	for head := 0; head < len(queue); head++ {
+		index := queue[head]
+		outerX, outerY := index%outerWidth, index/outerWidth
+		for _, offset := range offsets {
+			nextX, nextY := outerX+offset[0], outerY+offset[1]
+			if a.config.WrapEdges {
+				nextX, nextY = modInt(nextX, outerWidth), modInt(nextY, outerHeight)
+			} else if nextX < 0 || nextY < 0 || nextX >= outerWidth || nextY >= outerHeight {
+				continue
+			}
+			if next := nextY*outerWidth + nextX; rings[next] < 0 {
+				rings[next] = rings[index] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	clearance := make([]int, width*height)
+	for gridY := 0; gridY < height; gridY++ {
+		for gridX := 0; gridX < width; gridX++ {
+			ring := rings[(gridY+pad)*outerWidth+gridX+pad]
+			// a wrapped grid without any obstacle
+			if ring < 0 {
+				ring = infCost
+			}
+			clearance[gridY*width+gridX] = ring
+		}
+	}
+	return clearance
+}
@@ -0,0 +1,69 @@
+package astar
+
+import "testing"
+
+func TestAstar_FindPathAgentRadius(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ]   S: StartNode
+	// [ ] [ ] [E] [ ] [ ] [ ] [ ] [ ] [ ] [ ]   E: EndNode
+	// [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ]   O: ObstacleNode
+	// [O] [O] [ ] [O] [O] [O] [ ] [ ] [ ] [O]
+	// [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ]   a point fits through the narrow gap,
+	// [ ] [ ] [S] [ ] [ ] [ ] [ ] [ ] [ ] [ ]   a unit of 3x3 cells only through the wide one
+	// [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ] [ ]
+
+	startNode := Node{X: 2, Y: 1}
+	endNode := Node{X: 2, Y: 5}
+	var obstacleNodes []Node
+	for _, x := range []int{0, 1, 3, 4, 5, 9} {
+		obstacleNodes = append(obstacleNodes, Node{X: x, Y: 3})
+	}
+
+	a, err := New(Config{GridWidth: 10, GridHeight: 7, InvalidNodes: obstacleNodes})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, err := a.FindPath(nil, startNode, endNode)
+	if err != nil || len(foundPath) != 5 {
+		t.Error("the point should take the narrow gap: ", foundPath, err)
+	}
+
+	a, err = New(Config{GridWidth: 10, GridHeight: 7, InvalidNodes: obstacleNodes, AgentRadius: 1})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, err = a.FindPath(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if len(foundPath) != 15 {
+		t.Error("the unit should take the wide gap: ", foundPath)
+	}
+	for _, node := range foundPath {
+		if a.Clearance(node.X, node.Y) <= 1 {
+			t.Error("the unit should not touch a wall: ", node)
+		}
+	}
+	if a.Clearance(2, 2) != 1 || a.Clearance(4, 3) != 0 || a.Clearance(0, 0) != 1 || a.Clearance(7, 3) != 2 {
+		t.Error("the clearance should be the distance to the walls and the border: ",
+			a.Clearance(2, 2), a.Clearance(4, 3), a.Clearance(0, 0), a.Clearance(7, 3))
+	}
+
+	// the blocks of the context are checked for the whole footprint
+	if _, err := a.FindPath(newContext(endNode.X, endNode.Y, 0, []Node{{X: 8, Y: 4}}), startNode, endNode); err != ErrorNoPath {
+		t.Error("the context should close the wide gap: ", err)
+	}
+
+	// the cached clearance follows the added obstacles
+	a.AddObstacle(7, 4)
+	if a.Clearance(7, 3) != 1 {
+		t.Error("the clearance should be computed again: ", a.Clearance(7, 3))
+	}
+	if _, err := a.FindPath(nil, startNode, endNode); err != ErrorNoPath {
+		t.Error("the wide gap should be too narrow now: ", err)
+	}
+
+	if _, err := New(Config{GridWidth: 10, GridHeight: 7, AgentRadius: -1}); err == nil {
+		t.Error("a negative AgentRadius should be an error")
+	}
+}
@@ -0,0 +1,224 @@
+package astar
+
+import "container/heap"
+
+// ReservationTable records which cells are taken by earlier units at which time step,
+// FindPathCooperative plans around them and adds the reservations of its path
+//
+// It is not safe for concurrent use, the units are planned one after the other
+type ReservationTable struct {
+	cells    map[[3]int]struct{} // 预约的格子和时间
+	parked   map[[2]int]int      // 从该时间起一直占用的格子
+	last     map[[2]int]int      // 每个格子最后被预约的时间
+	lastTime int                 // 所有预约中最晚的时间
+}
+
+// NewReservationTable creates an empty reservation table
+func NewReservationTable() *ReservationTable {
+	return &ReservationTable{
+		cells:  make(map[[3]int]struct{}),
+		parked: make(map[[2]int]int),
+		last:   make(map[[2]int]int),
+	}
+}
+
+// Reserve takes the cell at the time step t
+func (r *ReservationTable) Reserve(x, y, t int) {
+	r.cells[[3]int{x, y, t}] = struct{}{}
+	r.touch(x, y, t)
+}
+
+// IsReserved checks if the cell is taken at the time step t
+func (r *ReservationTable) IsReserved(x, y, t int) bool {
+	if _, ok := r.cells[[3]int{x, y, t}]; ok {
+		return true
+	}
+	since, ok := r.parked[[2]int{x, y}]
+	return ok && t >= since
+}
+
+// Clear removes all reservations
+func (r *ReservationTable) Clear() {
+	for key := range r.cells {
+		delete(r.cells, key)
+	}
+	for key := range r.parked {
+		delete(r.parked, key)
+	}
+	for key := range r.last {
+		delete(r.last, key)
+	}
+	r.lastTime = 0
+}
+
+// park takes the cell from the time step t on, a unit stays at its goal
+func (r *ReservationTable) park(x, y, t int) {
+	r.parked[[2]int{x, y}] = t
+	r.touch(x, y, t)
+}
+
+func (r *ReservationTable) touch(x, y, t int) {
+	if last, ok := r.last[[2]int{x, y}]; !ok || t > last {
+		r.last[[2]int{x, y}] = t
+	}
+	if t > r.lastTime {
+		r.lastTime = t
+	}
+}
+
+// FindPathCooperative searches a path in space and time which avoids the reservations of table
+// and reserves the cells of the found path, e.g. to move several units without collisions (WHCA*)
+//
+// Node i of the path is the cell of the unit at the time step i, a unit waiting for another one
+// stays in its cell so the node repeats. Two units do not share a cell at the same time step
+// and do not swap their cells in one step, the unit stays reserved at the end node after its arrival.
+// The costs of the steps are the ones of FindPath, waiting costs one orthogonal step.
+// ctx.IsNearEnough is not used, the path ends at the exact end node
+func (a *PathFinder) FindPathCooperative(ctx IContext, startNode, endNode Node, table *ReservationTable) ([]Node, error) {
+	a.obstacleMu.RLock()
+	defer a.obstacleMu.RUnlock()
+	endNode = a.snapEnd(ctx, endNode)
+
+	if err := a.checkEndpoints(ctx, startNode, endNode, false); err != nil {
+		return nil, err
+	}
+
+	// after the last reservation the table does not change anymore,
+	// so all later time steps of a cell are one state of the search
+	horizon := table.lastTime + 1
+	stateKey := func(x, y, t int) [3]int {
+		if t > horizon {
+			t = horizon
+		}
+		return [3]int{x, y, t}
+	}
+
+	type record struct {
+		node   Node
+		t      int
+		parent [3]int
+		root   bool
+	}
+	records := make(map[[3]int]record)
+	closed := make(map[[3]int]struct{})
+
+	startNode.parent = nil
+	startKey := stateKey(startNode.X, startNode.Y, 0)
+	records[startKey] = record{node: startNode, root: true}
+	startNode.h = a.hCost(startNode, endNode)
+	startNode.f = startNode.h
+	openList := timeHeap{{node: startNode, key: startKey}}
+
+	var neighbors []Node
+	for openList.Len() > 0 {
+		entry := heap.Pop(&openList).(timeEntry)
+		if _, ok := closed[entry.key]; ok {
+			continue
+		}
+		current := records[entry.key]
+		if current.node.g != entry.node.g {
+			continue
+		}
+		closed[entry.key] = struct{}{}
+
+		if current.node.X == endNode.X && current.node.Y == endNode.Y && table.canPark(endNode.X, endNode.Y, current.t) {
+			foundPath := make([]Node, current.t+1)
+			for key := entry.key; ; key = records[key].parent {
+				step := records[key]
+				foundPath[step.t] = step.node
+				if step.root {
+					break
+				}
+			}
+			for t, node := range foundPath {
+				table.Reserve(node.X, node.Y, t)
+			}
+			table.park(endNode.X, endNode.Y, current.t)
+			return foundPath, nil
+		}
+
+		currentNode := current.node
+		t := current.t
+		neighbors = a.appendNeighborNodes(ctx, neighbors[:0], &currentNode, nil)
+		// waiting is a step to the own cell
+		neighbors = append(neighbors, currentNode)
+		for _, neighbor := range neighbors {
+			if table.IsReserved(neighbor.X, neighbor.Y, t+1) {
+				continue
+			}
+			// two units must not swap their cells
+			if (neighbor.X != currentNode.X || neighbor.Y != currentNode.Y) &&
+				table.IsReserved(neighbor.X, neighbor.Y, t) && table.IsReserved(currentNode.X, currentNode.Y, t+1) {
+				continue
+			}
+
+			key := stateKey(neighbor.X, neighbor.Y, t+1)
+			if _, ok := closed[key]; ok {
+				continue
+			}
+			stepCost := a.straightCost()
+			if neighbor.X != currentNode.X || neighbor.Y != currentNode.Y {
+				stepCost = a.stepCost(currentNode, neighbor)
+			}
+			g := addCost(currentNode.g, stepCost)
+			if g >= infCost {
+				continue
+			}
+			if old, ok := records[key]; ok && old.node.g <= g {
+				continue
+			}
+
+			neighbor.parent = nil
+			neighbor.g = g
+			neighbor.h = a.hCost(neighbor, endNode)
+			neighbor.f = addCost(g, neighbor.h)
+			records[key] = record{node: neighbor, t: t + 1, parent: entry.key}
+			heap.Push(&openList, timeEntry{node: neighbor, key: key})
+		}
+	}
+
+	return nil, ErrorNoPath
+}
+
+// canPark checks if a unit can stay at the cell from the time step t on
+func (r *ReservationTable) canPark(x, y, t int) bool {
+	if _, ok := r.parked[[2]int{x, y}]; ok {
+		return false
+	}
+	last, ok := r.last[[2]int{x, y}]
+	return !ok || last < t
+}
+
+// timeEntry is a state of FindPathCooperative in the open list
+type timeEntry struct {
+	node Node
+	key  [3]int // X, Y和时间
+}
+
+// timeHeap is a min heap of timeEntry ordered by F, then H and then the key
+type timeHeap []timeEntry
+
+func (h timeHeap) Len() int { return len(h) }
+func (h timeHeap) Less(i, j int) bool {
+	nodeA, nodeB := &h[i].node, &h[j].node
+	if nodeA.f != nodeB.f {
+		return nodeA.f < nodeB.f
+	}
+	if nodeA.h != nodeB.h {
+		return nodeA.h < nodeB.h
+	}
+	for k := range h[i].key {
+		if h[i].key[k] != h[j].key[k] {
+			return h[i].key[k] < h[j].key[k]
+		}
+	}
+	return false
+}
+func (h timeHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *timeHeap) Push(x interface{}) { *h = append(*h, x.(timeEntry)) }
+func (h *timeHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
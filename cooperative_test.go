@@ -0,0 +1,76 @@
+package astar
+
+import "testing"
+
+// checkNoCollision fails if the units share a cell at a time step or swap their cells
+func checkNoCollision(t *testing.T, pathA, pathB []Node) {
+	at := func(path []Node, step int) Node {
+		if step < len(path) {
+			return path[step]
+		}
+		// a unit stays at its end node
+		return path[len(path)-1]
+	}
+	steps := len(pathA)
+	if len(pathB) > steps {
+		steps = len(pathB)
+	}
+	for step := 0; step < steps; step++ {
+		a, b := at(pathA, step), at(pathB, step)
+		if a.X == b.X && a.Y == b.Y {
+			t.Error("the units should not share a cell: ", step, a)
+		}
+		if step > 0 {
+			lastA, lastB := at(pathA, step-1), at(pathB, step-1)
+			if lastA.X == b.X && lastA.Y == b.Y && lastB.X == a.X && lastB.Y == a.Y {
+				t.Error("the units should not swap their cells: ", step, a, b)
+			}
+		}
+	}
+}
+
+func TestAstar_FindPathCooperative(t *testing.T) {
+
+	// [O] [O] [O] [ ] [O]   A: start of unit A, end of unit B
+	// [A] [ ] [ ] [ ] [B]   B: start of unit B, end of unit A
+	//                       O: ObstacleNode
+
+	obstacleNodes := []Node{{X: 0, Y: 1}, {X: 1, Y: 1}, {X: 2, Y: 1}, {X: 4, Y: 1}}
+	a, err := New(Config{GridWidth: 5, GridHeight: 2, InvalidNodes: obstacleNodes})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	nodeA, nodeB := Node{X: 0, Y: 0}, Node{X: 4, Y: 0}
+
+	table := NewReservationTable()
+	pathA, err := a.FindPathCooperative(nil, nodeA, nodeB, table)
+	if err != nil {
+		t.Fatal("there should be a path for unit A", err)
+	}
+	if len(pathA) != 5 {
+		t.Error("unit A should go straight: ", pathA)
+	}
+	if !table.IsReserved(2, 0, 2) || table.IsReserved(2, 0, 3) || !table.IsReserved(4, 0, 100) {
+		t.Error("the path of unit A should be reserved and its end node from then on")
+	}
+
+	// unit B waits in the niche until unit A has passed
+	pathB, err := a.FindPathCooperative(nil, nodeB, nodeA, table)
+	if err != nil {
+		t.Fatal("there should be a path for unit B", err)
+	}
+	if len(pathB) != 8 || pathB[3].X != 3 || pathB[3].Y != 1 {
+		t.Error("unit B should be in the niche when unit A passes it: ", pathB)
+	}
+	checkNoCollision(t, pathA, pathB)
+
+	// unit A stays at the end node of a third unit
+	if _, err = a.FindPathCooperative(nil, Node{X: 3, Y: 1}, nodeB, table); err != ErrorNoPath {
+		t.Error("the parked unit should block the end node: ", err)
+	}
+
+	table.Clear()
+	if table.IsReserved(4, 0, 100) {
+		t.Error("Clear should remove all reservations")
+	}
+}
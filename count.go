@@ -0,0 +1,96 @@
+package astar
+
+import (
+	"container/heap"
+	"math"
+)
+
+// PathCountLimit is the highest count of CountShortestPaths,
+// more shortest paths are reported as PathCountLimit
+const PathCountLimit = math.MaxInt32
+
+// CountShortestPaths returns the number of distinct paths from the start
+// to the end node which have the minimal cost, e.g. to find the symmetries and choke points of a map
+//
+// It runs a Dijkstra flood which sums for every cell the counts of the neighbors
+// it is reached from at its minimal cost, the flood stops once the end node is final.
+// The step costs are the ones of FindPath without TurnPenalty, every step must cost more than 0.
+// If the end node cannot be reached it returns 0 and ErrorNoPath
+func (a *PathFinder) CountShortestPaths(ctx IContext, startNode, endNode Node) (int, error) {
+	if a.config.Unbounded {
+		return 0, ErrUnboundedGrid
+	}
+	a.obstacleMu.RLock()
+	defer a.obstacleMu.RUnlock()
+
+	if err := a.checkEndpoints(ctx, startNode, endNode, false); err != nil {
+		return 0, err
+	}
+
+	width := a.config.GridWidth
+	costs := make([]int, width*a.config.GridHeight)
+	for i := range costs {
+		costs[i] = infCost
+	}
+	counts := make([]int, len(costs))
+	settled := make([]bool, len(costs))
+
+	buckets := map[int][]int{}
+	var bucketCosts costHeap
+	push := func(index, cost int) {
+		if _, ok := buckets[cost]; !ok {
+			heap.Push(&bucketCosts, cost)
+		}
+		buckets[cost] = append(buckets[cost], index)
+	}
+	startIndex, _ := a.cellIndex(startNode.X, startNode.Y)
+	endIndex, _ := a.cellIndex(endNode.X, endNode.Y)
+	costs[startIndex], counts[startIndex] = 0, 1
+	push(startIndex, 0)
+
+	var neighbors []Node
+	for bucketCosts.Len() > 0 && !settled[endIndex] {
+		cost := heap.Pop(&bucketCosts).(int)
+		cells := buckets[cost]
+		delete(buckets, cost)
+
+		for _, index := range cells {
+			// a cell can be in several buckets, only the cheapest one counts
+			if settled[index] || costs[index] != cost {
+				continue
+			}
+			// all cheaper cells are final, so is the count of the cell
+			settled[index] = true
+
+			node := Node{X: index%width + a.config.OriginX, Y: index/width + a.config.OriginY, g: cost}
+			neighbors = a.appendNeighborNodes(ctx, neighbors[:0], &node, nil)
+			for _, neighbor := range neighbors {
+				neighborCost := addCost(cost, a.stepCost(node, neighbor))
+				neighborIndex, _ := a.cellIndex(neighbor.X, neighbor.Y)
+				if neighborCost >= infCost || settled[neighborIndex] {
+					continue
+				}
+				switch {
+				case neighborCost < costs[neighborIndex]:
+					costs[neighborIndex], counts[neighborIndex] = neighborCost, counts[index]
+					push(neighborIndex, neighborCost)
+				case neighborCost == costs[neighborIndex]:
+					counts[neighborIndex] = addCount(counts[neighborIndex], counts[index])
+				}
+			}
+		}
+	}
+
+	if !settled[endIndex] {
+		return 0, ErrorNoPath
+	}
+	return counts[endIndex], nil
+}
+
+// addCount adds two path counts, the sum saturates at PathCountLimit
+func addCount(a, b int) int {
+	if a > PathCountLimit-b {
+		return PathCountLimit
+	}
+	return a + b
+}
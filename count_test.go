@@ -0,0 +1,56 @@
+package astar
+
+import "testing"
+
+func TestAstar_CountShortestPaths(t *testing.T) {
+	a, err := New(Config{GridWidth: 5, GridHeight: 5})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+
+	// 4 right and 4 up steps in any order
+	if count, err := a.CountShortestPaths(nil, Node{X: 0, Y: 0}, Node{X: 4, Y: 4}); err != nil || count != 70 {
+		t.Error("there should be 70 shortest paths: ", count, err)
+	}
+	if count, err := a.CountShortestPaths(nil, Node{X: 0, Y: 0}, Node{X: 4, Y: 0}); err != nil || count != 1 {
+		t.Error("a straight line should be the only shortest path: ", count, err)
+	}
+	if count, err := a.CountShortestPaths(nil, Node{X: 2, Y: 2}, Node{X: 2, Y: 2}); err != nil || count != 1 {
+		t.Error("the start node should be reached once: ", count, err)
+	}
+
+	// [ ] [ ] [O] [ ] [E]   S: StartNode
+	// [ ] [ ] [O] [ ] [ ]   E: EndNode
+	// [ ] [ ] [ ] [ ] [ ]   O: ObstacleNode
+	// [ ] [ ] [O] [ ] [ ]
+	// [S] [ ] [O] [ ] [ ]
+
+	// every shortest path passes the choke point
+	a, err = New(Config{GridWidth: 5, GridHeight: 5, InvalidNodes: []Node{
+		{X: 2, Y: 0}, {X: 2, Y: 1}, {X: 2, Y: 3}, {X: 2, Y: 4},
+	}})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	// 3 ways to the gap times 3 ways from it
+	if count, err := a.CountShortestPaths(nil, Node{X: 0, Y: 0}, Node{X: 4, Y: 4}); err != nil || count != 9 {
+		t.Error("there should be 9 shortest paths: ", count, err)
+	}
+
+	a.AddObstacle(2, 2)
+	if count, err := a.CountShortestPaths(nil, Node{X: 0, Y: 0}, Node{X: 4, Y: 4}); err != ErrorNoPath || count != 0 {
+		t.Error("there should be no path", count, err)
+	}
+	if _, err := a.CountShortestPaths(nil, Node{X: 0, Y: 0}, Node{X: 2, Y: 2}); err != ErrEndBlocked {
+		t.Error("end node should be blocked", err)
+	}
+
+	// the count saturates instead of overflowing
+	a, err = New(Config{GridWidth: 60, GridHeight: 60})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if count, err := a.CountShortestPaths(nil, Node{X: 0, Y: 0}, Node{X: 59, Y: 59}); err != nil || count != PathCountLimit {
+		t.Error("count should be capped: ", count, err)
+	}
+}
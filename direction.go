@@ -0,0 +1,136 @@
+package astar
+
+import (
+	"context"
+	"fmt"
+)
+
+// Direction is a single move between two neighbor nodes
+// Up increases Y, since the grid starts "bottom left"
+type Direction int
+
+const (
+	DirUp Direction = iota
+	DirDown
+	DirLeft
+	DirRight
+	DirUpLeft
+	DirUpRight
+	DirDownLeft
+	DirDownRight
+)
+
+// directionDeltas maps the X/Y delta of a move to its direction
+var directionDeltas = map[[2]int]Direction{
+	{0, 1}:   DirUp,
+	{0, -1}:  DirDown,
+	{-1, 0}:  DirLeft,
+	{1, 0}:   DirRight,
+	{-1, 1}:  DirUpLeft,
+	{1, 1}:   DirUpRight,
+	{-1, -1}: DirDownLeft,
+	{1, -1}:  DirDownRight,
+}
+
+// String returns the name of the direction
+func (d Direction) String() string {
+	switch d {
+	case DirUp:
+		return "Up"
+	case DirDown:
+		return "Down"
+	case DirLeft:
+		return "Left"
+	case DirRight:
+		return "Right"
+	case DirUpLeft:
+		return "UpLeft"
+	case DirUpRight:
+		return "UpRight"
+	case DirDownLeft:
+		return "DownLeft"
+	case DirDownRight:
+		return "DownRight"
+	}
+	return fmt.Sprintf("Direction(%d)", int(d))
+}
+
+// Delta returns the X/Y change of the move
+func (d Direction) Delta() (dx, dy int) {
+	for delta, direction := range directionDeltas {
+		if direction == d {
+			return delta[0], delta[1]
+		}
+	}
+	return 0, 0
+}
+
+// FindPathDirections works like FindPath but returns the moves
+// from the start to the end node instead of the nodes,
+// the result has one element less than the path of FindPath,
+// with WrapEdges a step over the seam is the move across the border
+func (a *PathFinder) FindPathDirections(ctx IContext, startNode, endNode Node) ([]Direction, error) {
+	foundPath, err := a.FindPath(ctx, startNode, endNode)
+	if err != nil {
+		return nil, err
+	}
+	return pathDirections(foundPath, a.stepDelta)
+}
+
+// approachPenalty is added to the cost of reaching the end node of FindPathDirected
+// from the wrong direction, it outweighs any detour of a real grid
+// but leaves room below infCost so the path is still found
+const approachPenalty = infCost / 4
+
+// FindPathDirected works like FindPath but the last step of the path
+// should arrive at the end node moving in finalDir, e.g. a vehicle parking forward.
+//
+// Reaching the end node from another direction costs a huge penalty, so the
+// search takes any detour to arrive facing finalDir and only falls back to another
+// heading if the cell before the end node cannot be reached or finalDir is not
+// a move of the grid, e.g. a diagonal without AllowDiagonal.
+// The costs of the returned nodes do not include the penalty
+func (a *PathFinder) FindPathDirected(ctx IContext, startNode, endNode Node, finalDir Direction) ([]Node, error) {
+	dx, dy := finalDir.Delta()
+	if dx == 0 && dy == 0 {
+		return nil, fmt.Errorf("unknown direction %v", finalDir)
+	}
+
+	opts := searchOptions{maxSteps: StepsNoLimit, directed: true, approach: [2]int{dx, dy}}
+	foundPath, err := a.doFindPath(context.Background(), ctx, startNode, endNode, opts)
+	if err != nil || len(foundPath) < 2 {
+		return foundPath, err
+	}
+	last := &foundPath[len(foundPath)-1]
+	if last.X == endNode.X && last.Y == endNode.Y && a.stepDelta(foundPath[len(foundPath)-2], *last) != opts.approach {
+		last.g -= approachPenalty
+		last.f -= approachPenalty
+	}
+	return foundPath, nil
+}
+
+// PathDirections converts a path in start to goal order into its moves
+// it returns an error if two consecutive nodes are not neighbors,
+// e.g. for the paths of FindPathTheta or a portal of Config.Neighbors
+func PathDirections(path []Node) ([]Direction, error) {
+	return pathDirections(path, func(from, to Node) [2]int {
+		return [2]int{to.X - from.X, to.Y - from.Y}
+	})
+}
+
+// pathDirections converts the path with the given X/Y change of a step
+func pathDirections(path []Node, delta func(from, to Node) [2]int) ([]Direction, error) {
+	if len(path) < 2 {
+		return nil, nil
+	}
+
+	directions := make([]Direction, 0, len(path)-1)
+	for i := 1; i < len(path); i++ {
+		direction, ok := directionDeltas[delta(path[i-1], path[i])]
+		if !ok {
+			return nil, fmt.Errorf("nodes %v and %v are not neighbors", path[i-1], path[i])
+		}
+		directions = append(directions, direction)
+	}
+	return directions, nil
+}
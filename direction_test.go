@@ -0,0 +1,174 @@
+package astar
+
+import "testing"
+
+func TestAstar_FindPathDirections(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [ ]   S: StartNode
+	// [ ] [ ] [P] [E] [ ]   E: EndNode
+	// [ ] [P] [O] [ ] [ ]   O: ObstacleNode
+	// [ ] [S] [O] [ ] [ ]   P: Valid Path
+	// [ ] [ ] [ ] [ ] [ ]
+
+	startNode := Node{X: 1, Y: 1}
+	endNode := Node{X: 3, Y: 3}
+	obstacleNodes := []Node{
+		{X: 2, Y: 1},
+		{X: 2, Y: 2},
+	}
+
+	a, err := New(Config{GridWidth: 5, GridHeight: 5, InvalidNodes: obstacleNodes, AllowDiagonal: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, err := a.FindPath(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	directions, err := a.FindPathDirections(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if len(directions) != len(foundPath)-1 {
+		t.Fatal("there should be one direction per step: ", directions)
+	}
+
+	// walking the directions reproduces the path
+	x, y := startNode.X, startNode.Y
+	for i, direction := range directions {
+		dx, dy := direction.Delta()
+		x, y = x+dx, y+dy
+		if x != foundPath[i+1].X || y != foundPath[i+1].Y {
+			t.Error("direction should lead to the next node: ", direction, foundPath[i+1])
+		}
+	}
+	want := []Direction{DirUp, DirUpRight, DirRight}
+	for i := range want {
+		if directions[i] != want[i] {
+			t.Error("wrong direction: ", directions)
+		}
+	}
+}
+
+func TestPathDirections(t *testing.T) {
+	path := []Node{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}, {X: 0, Y: 0}}
+	directions, err := PathDirections(path)
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	want := []Direction{DirUp, DirRight, DirDownLeft}
+	for i := range want {
+		if directions[i] != want[i] {
+			t.Error("wrong direction: ", directions[i], want[i])
+		}
+	}
+	if DirDownLeft.String() != "DownLeft" {
+		t.Error("should be DownLeft: ", DirDownLeft)
+	}
+
+	// nodes which are not neighbors
+	if _, err = PathDirections([]Node{{X: 0, Y: 0}, {X: 2, Y: 0}}); err == nil {
+		t.Error("there should be an error")
+	}
+	if directions, _ = PathDirections([]Node{{X: 0, Y: 0}}); len(directions) != 0 {
+		t.Error("single node should have no moves")
+	}
+}
+
+func TestAstar_FindPathDirected(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [ ]   S: StartNode
+	// [ ] [P] [P] [P] [ ]   E: EndNode
+	// [S] [P] [E] [P] [ ]   P: Valid Path, arrives moving left
+	// [ ] [ ] [ ] [ ] [ ]
+	// [ ] [ ] [ ] [ ] [ ]
+
+	startNode := Node{X: 0, Y: 2}
+	endNode := Node{X: 2, Y: 2}
+
+	a, err := New(Config{GridWidth: 5, GridHeight: 5})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, err := a.FindPathDirected(nil, startNode, endNode, DirLeft)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	directions, err := PathDirections(foundPath)
+	if err != nil || directions[len(directions)-1] != DirLeft {
+		t.Error("path should arrive moving left: ", directions, err)
+	}
+	if last := foundPath[len(foundPath)-1]; len(foundPath) != 7 || last.X != endNode.X || last.Y != endNode.Y || last.g != 6 {
+		t.Error("path should take the shortest detour: ", foundPath)
+	}
+
+	// the matching heading costs nothing extra
+	if foundPath, err = a.FindPathDirected(nil, startNode, endNode, DirRight); err != nil || len(foundPath) != 3 {
+		t.Error("path should go straight: ", foundPath, err)
+	}
+
+	// the cell before the end node is blocked, the path arrives from another side
+	a.AddObstacle(3, 2)
+	foundPath, err = a.FindPathDirected(nil, startNode, endNode, DirLeft)
+	if err != nil || len(foundPath) != 3 || foundPath[2].g != 2 {
+		t.Error("path should fall back to another heading without the penalty: ", foundPath, err)
+	}
+
+	if _, err = a.FindPathDirected(nil, startNode, endNode, Direction(42)); err == nil {
+		t.Error("unknown direction should be an error")
+	}
+}
+
+// countTurns returns the number of direction changes of the path
+func countTurns(t *testing.T, path []Node) int {
+	directions, err := PathDirections(path)
+	if err != nil {
+		t.Fatal("path should be connected", err)
+	}
+	turns := 0
+	for i := 1; i < len(directions); i++ {
+		if directions[i] != directions[i-1] {
+			turns++
+		}
+	}
+	return turns
+}
+
+func TestAstar_TurnPenalty(t *testing.T) {
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 9, Y: 9}
+
+	// the cross product tie breaking follows the straight line with a staircase
+	a, err := New(Config{GridWidth: 10, GridHeight: 10, TieBreaking: TieBreakCrossProduct})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	plainPath, err := a.FindPath(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+
+	a, err = New(Config{GridWidth: 10, GridHeight: 10, TieBreaking: TieBreakCrossProduct, TurnPenalty: 1})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, cost, err := a.FindPathWithCost(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+
+	// the same length, a single turn instead of a zigzag
+	if len(foundPath) != len(plainPath) {
+		t.Error("path should be as long as without the penalty: ", foundPath)
+	}
+	if turns, plainTurns := countTurns(t, foundPath), countTurns(t, plainPath); turns != 1 || plainTurns <= turns {
+		t.Error("path should have fewer turns with the penalty: ", turns, plainTurns)
+	}
+	if cost != 19 {
+		t.Error("cost should include one turn: ", cost)
+	}
+
+	if _, err = New(Config{GridWidth: 10, GridHeight: 10, TurnPenalty: -1}); err == nil {
+		t.Error("negative TurnPenalty should be invalid")
+	}
+}
@@ -0,0 +1,55 @@
+package astar
+
+// Edge is the border between two neighboring cells, like a thin wall of a maze
+// it blocks the step between From and To in both directions
+type Edge struct {
+	From, To Node
+}
+
+// edgeKey returns the map key of the edge between two cells,
+// the smaller cell comes first so both directions share the key
+func edgeKey(nodeA, nodeB Node) [4]int {
+	if nodeB.X < nodeA.X || (nodeB.X == nodeA.X && nodeB.Y < nodeA.Y) {
+		nodeA, nodeB = nodeB, nodeA
+	}
+	return [4]int{nodeA.X, nodeA.Y, nodeB.X, nodeB.Y}
+}
+
+// isEdgeBlocked checks if the step from one cell to the other crosses a blocked edge
+//
+// a diagonal step passes the corner between the four cells,
+// it is rejected if one of the four edges touching that corner is blocked
+func (a *PathFinder) isEdgeBlocked(from, to Node) bool {
+	if len(a.blockedEdges) == 0 {
+		return false
+	}
+	if _, ok := a.blockedEdges[a.wrappedEdgeKey(from, to)]; ok {
+		return true
+	}
+
+	dx, dy := to.X-from.X, to.Y-from.Y
+	if absInt(dx) != 1 || absInt(dy) != 1 {
+		return false
+	}
+	sideX := Node{X: to.X, Y: from.Y}
+	sideY := Node{X: from.X, Y: to.Y}
+	for _, key := range [4][4]int{
+		a.wrappedEdgeKey(from, sideX), a.wrappedEdgeKey(from, sideY),
+		a.wrappedEdgeKey(sideX, to), a.wrappedEdgeKey(sideY, to),
+	} {
+		if _, ok := a.blockedEdges[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// wrappedEdgeKey returns the edgeKey of two cells, with WrapEdges
+// the cells beyond the border are mapped onto the grid first
+func (a *PathFinder) wrappedEdgeKey(nodeA, nodeB Node) [4]int {
+	if a.config.WrapEdges {
+		nodeA.X, nodeA.Y = a.wrapXY(nodeA.X, nodeA.Y)
+		nodeB.X, nodeB.Y = a.wrapXY(nodeB.X, nodeB.Y)
+	}
+	return edgeKey(nodeA, nodeB)
+}
@@ -0,0 +1,60 @@
+package astar
+
+import "testing"
+
+func TestAstar_FindPathBlockedEdges(t *testing.T) {
+
+	// [ ] [ ] [ ]   S: StartNode
+	//      ---      E: EndNode
+	// [S] [ ]|[E]   - and |: BlockedEdges
+	//      ---
+	// [ ] [ ] [ ]
+
+	startNode := Node{X: 0, Y: 1}
+	endNode := Node{X: 2, Y: 1}
+	edges := []Edge{
+		{From: Node{X: 1, Y: 1}, To: Node{X: 2, Y: 1}},
+		{From: Node{X: 1, Y: 2}, To: Node{X: 1, Y: 1}},
+		{From: Node{X: 1, Y: 0}, To: Node{X: 1, Y: 1}},
+	}
+
+	a, err := New(Config{GridWidth: 3, GridHeight: 3, BlockedEdges: edges})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, err := a.FindPath(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	// around the walls over the top or the bottom row
+	if len(foundPath) != 5 {
+		t.Error("path should go around the walls: ", foundPath)
+	}
+	for i := 1; i < len(foundPath); i++ {
+		if a.isEdgeBlocked(foundPath[i-1], foundPath[i]) {
+			t.Error("path crosses a wall: ", foundPath[i-1], foundPath[i])
+		}
+	}
+
+	// the walls block both directions
+	foundPath, err = a.FindPath(nil, endNode, Node{X: 1, Y: 1})
+	if err != nil || len(foundPath) != 6 {
+		t.Error("path into the walled cell should enter from the left: ", foundPath, err)
+	}
+
+	// a diagonal step cannot pass the end of a wall
+	a, err = New(Config{GridWidth: 3, GridHeight: 3, AllowDiagonal: true, BlockedEdges: edges[:1]})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if !a.isEdgeBlocked(Node{X: 1, Y: 0}, Node{X: 2, Y: 1}) || !a.isEdgeBlocked(Node{X: 2, Y: 2}, Node{X: 1, Y: 1}) {
+		t.Error("diagonal steps touching the wall should be blocked")
+	}
+	if a.isEdgeBlocked(Node{X: 0, Y: 0}, Node{X: 1, Y: 1}) {
+		t.Error("diagonal step away from the wall should be free")
+	}
+	foundPath, err = a.FindPathJPS(nil, Node{X: 1, Y: 1}, endNode)
+	if err != nil || len(foundPath) != 4 {
+		t.Error("path should step around the wall: ", foundPath, err)
+	}
+}
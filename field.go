@@ -0,0 +1,203 @@
+package astar
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"sync"
+)
+
+// DistanceField returns the minimal cost from the start node to every reachable cell
+// keyed by the X/Y coordinates, the start node itself has the cost 0
+//
+// It runs a single Dijkstra flood without heuristic over the whole grid,
+// InvalidNodes, ctx.IsInBlock and the weights are respected like in FindPath.
+// Unreachable cells are not part of the map
+func (a *PathFinder) DistanceField(ctx IContext, startNode Node) (map[[2]int]int, error) {
+	if a.config.Unbounded {
+		return nil, ErrUnboundedGrid
+	}
+	field := make(map[[2]int]int)
+	opts := searchOptions{
+		maxSteps: StepsNoLimit,
+		flood:    true,
+		onExpand: func(node Node) {
+			field[nodeKey(node)] = node.g
+		},
+	}
+	// the flood ends once the open list is empty
+	if _, err := a.doFindPath(context.Background(), ctx, startNode, startNode, opts); err != nil && err != ErrorNoPath {
+		return nil, err
+	}
+	return field, nil
+}
+
+// FlowField returns for every cell which can reach the goal the direction
+// of its cheapest next step towards the goal, keyed by the X/Y coordinates
+//
+// It runs a single reverse Dijkstra flood from the goal, so any number of units
+// can follow the field without an own search. The goal itself and unreachable
+// cells are not part of the map. The reverse flood expects the neighbors to be
+// symmetric, with Config.Neighbors the moves to distant cells are left out
+func (a *PathFinder) FlowField(ctx IContext, goalNode Node) (map[[2]int]Direction, error) {
+	if a.config.Unbounded {
+		return nil, ErrUnboundedGrid
+	}
+	field := make(map[[2]int]Direction)
+	opts := searchOptions{
+		maxSteps: StepsNoLimit,
+		flood:    true,
+		backward: true,
+		onExpand: func(node Node) {
+			if node.parent == nil {
+				return
+			}
+			// the parent of the reverse search is the next step to the goal
+			if direction, ok := directionDeltas[a.stepDelta(node, *node.parent)]; ok {
+				field[nodeKey(node)] = direction
+			}
+		},
+	}
+	if _, err := a.doFindPath(context.Background(), ctx, goalNode, goalNode, opts); err != nil && err != ErrorNoPath {
+		return nil, err
+	}
+	return field, nil
+}
+
+// DistanceFieldParallel returns the same costs as DistanceField
+// but spreads the work of the flood over workers goroutines
+//
+// It runs Dial's algorithm with a bucket for every cost: all cells of the cheapest bucket
+// are final, so their neighbors are generated and priced by the workers at the same time
+// and the cheaper costs are merged afterwards. It pays off for big fields with expensive
+// neighbors or costs, the IContext and the CostFunc must be safe for concurrent use
+func (a *PathFinder) DistanceFieldParallel(ctx IContext, startNode Node, workers int) (map[[2]int]int, error) {
+	if workers < 1 {
+		return nil, errors.New("workers must be min 1")
+	}
+	if a.config.Unbounded {
+		return nil, ErrUnboundedGrid
+	}
+
+	a.obstacleMu.RLock()
+	defer a.obstacleMu.RUnlock()
+
+	if err := a.checkNode(ctx, startNode, ErrStartBlocked); err != nil {
+		return nil, err
+	}
+
+	width := a.config.GridWidth
+	costs := make([]int, width*a.config.GridHeight)
+	for i := range costs {
+		costs[i] = infCost
+	}
+	settled := make([]bool, len(costs))
+
+	buckets := map[int][]int{}
+	var bucketCosts costHeap
+	push := func(index, cost int) {
+		if _, ok := buckets[cost]; !ok {
+			heap.Push(&bucketCosts, cost)
+		}
+		buckets[cost] = append(buckets[cost], index)
+	}
+	startIndex, _ := a.cellIndex(startNode.X, startNode.Y)
+	costs[startIndex] = 0
+	push(startIndex, 0)
+
+	// every worker collects the neighbors of its part of the bucket
+	type relaxation struct {
+		index, cost int
+	}
+	found := make([][]relaxation, workers)
+	expand := func(w int, cells []int) {
+		var neighbors []Node
+		found[w] = found[w][:0]
+		for _, index := range cells {
+			node := Node{X: index%width + a.config.OriginX, Y: index/width + a.config.OriginY, g: costs[index]}
+			neighbors = a.appendNeighborNodes(ctx, neighbors[:0], &node, nil)
+			for _, neighbor := range neighbors {
+				cost := addCost(node.g, a.stepCost(node, neighbor))
+				if cost >= infCost {
+					continue
+				}
+				neighborIndex, _ := a.cellIndex(neighbor.X, neighbor.Y)
+				found[w] = append(found[w], relaxation{index: neighborIndex, cost: cost})
+			}
+		}
+	}
+
+	for bucketCosts.Len() > 0 {
+		cost := bucketCosts[0]
+		var cells []int
+		for _, index := range buckets[cost] {
+			// a cell can be in several buckets, only the cheapest one counts
+			if !settled[index] && costs[index] == cost {
+				settled[index] = true
+				cells = append(cells, index)
+			}
+		}
+		delete(buckets, cost)
+		heap.Pop(&bucketCosts)
+
+		// small buckets are not worth the goroutines
+		parts := workers
+		if len(cells) < parallelFieldMinCells*workers {
+			parts = 1
+		}
+		if parts == 1 {
+			expand(0, cells)
+		} else {
+			var wg sync.WaitGroup
+			size := (len(cells) + parts - 1) / parts
+			for w := 0; w < parts; w++ {
+				from, to := w*size, (w+1)*size
+				if to > len(cells) {
+					to = len(cells)
+				}
+				wg.Add(1)
+				go func(w int, part []int) {
+					defer wg.Done()
+					expand(w, part)
+				}(w, cells[from:to])
+			}
+			wg.Wait()
+		}
+
+		// zero cost steps may add cells to the current bucket again
+		for w := 0; w < parts; w++ {
+			for _, r := range found[w] {
+				if r.cost < costs[r.index] {
+					costs[r.index] = r.cost
+					push(r.index, r.cost)
+				}
+			}
+		}
+	}
+
+	field := make(map[[2]int]int)
+	for index, cost := range costs {
+		if settled[index] {
+			field[[2]int{index%width + a.config.OriginX, index/width + a.config.OriginY}] = cost
+		}
+	}
+	return field, nil
+}
+
+// parallelFieldMinCells is the number of cells per worker
+// below which a bucket of DistanceFieldParallel is expanded by one goroutine
+const parallelFieldMinCells = 64
+
+// costHeap is a min heap of the bucket costs of DistanceFieldParallel and CountShortestPaths
+type costHeap []int
+
+func (h costHeap) Len() int            { return len(h) }
+func (h costHeap) Less(i, j int) bool  { return h[i] < h[j] }
+func (h costHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *costHeap) Push(x interface{}) { *h = append(*h, x.(int)) }
+func (h *costHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,170 @@
+package astar
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAstar_DistanceField(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ]   S: StartNode
+	// [ ] [O] [O] [ ]   O: ObstacleNode
+	// [S] [W] [ ] [X]   W: WeightedNode
+	//                   X: blocked by the context
+
+	startNode := Node{X: 0, Y: 0}
+	obstacleNodes := []Node{
+		{X: 1, Y: 1},
+		{X: 2, Y: 1},
+	}
+	a, err := New(Config{
+		GridWidth:     4,
+		GridHeight:    3,
+		InvalidNodes:  obstacleNodes,
+		WeightedNodes: []Node{{X: 1, Y: 0, Weighting: 5}},
+	})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+
+	ctx := newContext(-1, -1, -1, []Node{{X: 3, Y: 0}})
+	field, err := a.DistanceField(ctx, startNode)
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+
+	// 12 cells, 2 obstacles, 1 blocked
+	if len(field) != 9 {
+		t.Error("field should have 9 reachable cells: ", field)
+	}
+	want := map[[2]int]int{
+		{0, 0}: 0,
+		{1, 0}: 6,
+		{2, 0}: 7,
+		{0, 2}: 2,
+		{3, 2}: 5,
+		{3, 1}: 6,
+	}
+	for key, cost := range want {
+		if field[key] != cost {
+			t.Error("wrong cost: ", key, field[key], cost)
+		}
+	}
+	for _, node := range obstacleNodes {
+		if _, ok := field[nodeKey(node)]; ok {
+			t.Error("obstacle should not be part of the field: ", node)
+		}
+	}
+
+	// every cost matches the one of FindPath
+	for key, cost := range field {
+		_, pathCost, err := a.FindPathWithCost(ctx, startNode, Node{X: key[0], Y: key[1]})
+		if err != nil || pathCost != cost {
+			t.Error("field should match FindPath: ", key, cost, pathCost, err)
+		}
+	}
+}
+
+func TestAstar_FlowField(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [ ]   G: GoalNode
+	// [ ] [O] [O] [O] [ ]   O: ObstacleNode
+	// [ ] [ ] [G] [O] [ ]   W: WeightedNode
+	// [ ] [O] [O] [O] [ ]
+	// [ ] [ ] [W] [ ] [ ]
+
+	goalNode := Node{X: 2, Y: 2}
+	obstacleNodes := []Node{
+		{X: 1, Y: 3}, {X: 2, Y: 3}, {X: 3, Y: 3},
+		{X: 3, Y: 2},
+		{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 3, Y: 1},
+	}
+	config := Config{
+		GridWidth:     5,
+		GridHeight:    5,
+		InvalidNodes:  obstacleNodes,
+		WeightedNodes: []Node{{X: 2, Y: 0, Weighting: 20}},
+		AllowDiagonal: true,
+	}
+	a, err := New(config)
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+
+	field, err := a.FlowField(nil, goalNode)
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if _, ok := field[nodeKey(goalNode)]; ok {
+		t.Error("goal should have no direction")
+	}
+	// 25 cells, 7 obstacles and the goal
+	if len(field) != 17 {
+		t.Error("every other cell should have a direction: ", len(field))
+	}
+
+	// following the field from every cell reaches the goal with the optimal cost
+	for key := range field {
+		startNode := Node{X: key[0], Y: key[1]}
+		_, wantCost, err := a.FindPathWithCost(nil, startNode, goalNode)
+		if err != nil {
+			t.Fatal("there should be a path", err)
+		}
+
+		current := startNode
+		cost := 0
+		for steps := 0; current != goalNode; steps++ {
+			if steps > 25 {
+				t.Fatal("field should lead to the goal: ", key)
+			}
+			dx, dy := field[nodeKey(current)].Delta()
+			next := Node{X: current.X + dx, Y: current.Y + dy}
+			cost += a.stepCost(current, next)
+			current = next
+		}
+		if cost != wantCost {
+			t.Error("field should follow the cheapest path: ", key, cost, wantCost)
+		}
+	}
+
+	// unreachable cells are absent
+	a.AddObstacle(1, 2)
+	if field, _ = a.FlowField(nil, goalNode); len(field) != 0 {
+		t.Error("enclosed goal should have an empty field: ", field)
+	}
+}
+
+func TestAstar_DistanceFieldParallel(t *testing.T) {
+	var obstacleNodes, weightedNodes []Node
+	for i := 0; i < 150; i++ {
+		obstacleNodes = append(obstacleNodes, Node{X: 100, Y: i}, Node{X: i + 30, Y: 120})
+		weightedNodes = append(weightedNodes, Node{X: 50, Y: i + 10, Weighting: 7})
+	}
+	weightedNodes = withoutInvalidNodes(weightedNodes, obstacleNodes)
+	a, err := New(Config{GridWidth: 200, GridHeight: 200, InvalidNodes: obstacleNodes, WeightedNodes: weightedNodes, AllowDiagonal: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	startNode := Node{X: 20, Y: 20}
+
+	want, err := a.DistanceField(nil, startNode)
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	for _, workers := range []int{1, 4} {
+		field, err := a.DistanceFieldParallel(nil, startNode, workers)
+		if err != nil {
+			t.Fatal("there should be no error", err)
+		}
+		if !reflect.DeepEqual(field, want) {
+			t.Error("parallel field should match the serial one: ", workers, len(field), len(want))
+		}
+	}
+
+	if _, err = a.DistanceFieldParallel(nil, Node{X: 100, Y: 0}, 4); err != ErrStartBlocked {
+		t.Error("start node should be blocked", err)
+	}
+	if _, err = a.DistanceFieldParallel(nil, startNode, 0); err == nil {
+		t.Error("0 workers should be invalid")
+	}
+}
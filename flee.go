@@ -0,0 +1,52 @@
+package astar
+
+import "context"
+
+// FindFleePath searches an escape route from the start node away from the threat,
+// e.g. for a unit running from an enemy. The path leads to the expanded cell with the largest
+// distance to the threat, of equally far cells to the cheapest one, and is ordered from the start
+// to that cell. If no reachable cell is farther than the start node it holds only the start node
+//
+// There is no goal, the H of a cell is a potential which falls with its distance to the threat,
+// so the search expands the cells away from it first and stops after steps expanded nodes.
+// With StepsNoLimit it floods every reachable cell, on an Unbounded grid it returns ErrUnboundedGrid then.
+// The threat itself may be blocked.
+// The distance is the heuristic of FindPath, ctx.IsNearEnough is not used
+func (a *PathFinder) FindFleePath(ctx IContext, startNode, threatNode Node, steps int) ([]Node, error) {
+	if steps == StepsNoLimit && a.config.Unbounded {
+		return nil, ErrUnboundedGrid
+	}
+	var bestPath []Node
+	bestDist, bestG := -1, 0
+	opts := searchOptions{
+		maxSteps: steps,
+		flood:    true,
+		onExpand: func(node Node) {
+			dist := a.hCost(node, threatNode)
+			if dist > bestDist || (dist == bestDist && node.g < bestG) {
+				bestDist, bestG = dist, node.g
+				bestPath = a.getNodePath(node)
+			}
+		},
+	}
+	// without a limit every reachable cell is expanded, the order does not matter then
+	if steps > 0 {
+		// no cell within steps moves is farther than limit, so the potential stays positive
+		maxStep := a.config.OrthogonalCost
+		if a.config.DiagonalCost > maxStep {
+			maxStep = a.config.DiagonalCost
+		}
+		limit := addCost(a.hCost(startNode, threatNode), steps*maxStep)
+		opts.goalH = func(node Node) int {
+			if dist := a.hCost(node, threatNode); dist < limit {
+				return limit - dist
+			}
+			return 0
+		}
+	}
+	_, err := a.doFindPath(context.Background(), ctx, startNode, threatNode, opts)
+	if err != nil && err != ErrorNoPath && err != ErrStepLimitReached {
+		return nil, err
+	}
+	return bestPath, nil
+}
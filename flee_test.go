@@ -0,0 +1,88 @@
+package astar
+
+import "testing"
+
+func TestAstar_FindFleePath(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [ ] [ ] [ ]   S: StartNode
+	// [ ] [ ] [T] [S] [ ] [ ] [ ]   T: ThreatNode
+	// [ ] [ ] [ ] [ ] [ ] [ ] [ ]   the farthest cells are in the right corners
+
+	startNode := Node{X: 3, Y: 1}
+	threatNode := Node{X: 2, Y: 1}
+
+	a, err := New(Config{GridWidth: 7, GridHeight: 3})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, err := a.FindFleePath(nil, startNode, threatNode, 20)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	lastNode := foundPath[len(foundPath)-1]
+	if foundPath[0].X != startNode.X || foundPath[0].Y != startNode.Y || lastNode.X != 6 || lastNode.Y == 1 {
+		t.Error("the path should lead into a right corner: ", foundPath)
+	}
+	if len(foundPath) != 5 {
+		t.Error("the path should be one of the cheapest to the corner: ", foundPath)
+	}
+
+	// the budget only covers the start node
+	foundPath, err = a.FindFleePath(nil, startNode, threatNode, 1)
+	if err != nil || len(foundPath) != 1 {
+		t.Error("the path should stay at the start node: ", foundPath, err)
+	}
+
+	if _, err := a.FindFleePath(nil, Node{X: 7, Y: 1}, threatNode, 20); err != ErrOutOfBounds {
+		t.Error("a start node out of bounds should be an error: ", err)
+	}
+}
+
+func TestAstar_FindFleePathWall(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [O] [ ] [ ]   S: StartNode
+	// [ ] [ ] [T] [S] [O] [ ] [ ]   T: ThreatNode
+	// [ ] [ ] [ ] [ ] [O] [ ] [ ]   O: ObstacleNode
+	//
+	// the right side cannot be reached, the left corners are the farthest cells
+
+	startNode := Node{X: 3, Y: 1}
+	threatNode := Node{X: 2, Y: 1}
+	obstacleNodes := []Node{{X: 4, Y: 0}, {X: 4, Y: 1}, {X: 4, Y: 2}}
+
+	a, err := New(Config{GridWidth: 7, GridHeight: 3, InvalidNodes: obstacleNodes})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, err := a.FindFleePath(nil, startNode, threatNode, StepsNoLimit)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	lastNode := foundPath[len(foundPath)-1]
+	if lastNode.X != 0 || lastNode.Y == 1 {
+		t.Error("the path should lead into a left corner: ", foundPath)
+	}
+	for _, node := range foundPath {
+		if node.X == 4 {
+			t.Error("the path should not cross the wall: ", foundPath)
+		}
+	}
+}
+
+func TestAstar_FindFleePathUnbounded(t *testing.T) {
+	a, err := New(Config{Unbounded: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	startNode, threatNode := Node{X: 0, Y: 0}, Node{X: -1, Y: 0}
+	if _, err := a.FindFleePath(nil, startNode, threatNode, StepsNoLimit); err != ErrUnboundedGrid {
+		t.Error("an unlimited flee should be rejected on an unbounded grid", err)
+	}
+	foundPath, err := a.FindFleePath(nil, startNode, threatNode, 20)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if lastNode := foundPath[len(foundPath)-1]; ManhattanDistance(lastNode, threatNode) <= ManhattanDistance(startNode, threatNode) {
+		t.Error("the path should lead away from the threat: ", foundPath)
+	}
+}
@@ -0,0 +1,241 @@
+package astar
+
+import (
+	"container/heap"
+	"math"
+)
+
+// PathFinderF is a PathFinder which sums the costs as float64 instead of int,
+// a diagonal step costs exactly OrthogonalCost * sqrt(2) instead of DiagonalCost
+// and CellWidth and CellHeight are not rounded to the step costs
+//
+// It shares the Config, the obstacles and the neighbors of the embedded PathFinder,
+// only FindPath and FindPathWithCost search with the float costs.
+// The int mode is faster: the float search allocates its cell storage for every
+// search and does not use the pooled open list. Float sums of different paths are rarely
+// exactly equal, so ties are broken by rounding noise, and G stays exact up to 2^53
+// only. Prefer the int mode with OrthogonalCost 10 or 100 if the rounding is good enough
+type PathFinderF struct {
+	*PathFinder
+}
+
+// NewF creates a PathFinderF with the same config rules as New
+func NewF(config Config) (*PathFinderF, error) {
+	finder, err := New(config)
+	if err != nil {
+		return nil, err
+	}
+	return &PathFinderF{PathFinder: finder}, nil
+}
+
+// FindPath works like PathFinder.FindPath but compares the float costs,
+// the int costs of the returned nodes are not set
+func (a *PathFinderF) FindPath(ctx IContext, startNode, endNode Node) ([]Node, error) {
+	foundPath, _, err := a.FindPathWithCost(ctx, startNode, endNode)
+	return foundPath, err
+}
+
+// FindPathWithCost works like PathFinder.FindPathWithCost with float costs,
+// the cost includes the weighting and the fractional step lengths
+func (a *PathFinderF) FindPathWithCost(ctx IContext, startNode, endNode Node) ([]Node, float64, error) {
+	if a.config.Unbounded {
+		return nil, 0, ErrUnboundedGrid
+	}
+	a.obstacleMu.RLock()
+	defer a.obstacleMu.RUnlock()
+	endNode = a.snapEnd(ctx, endNode)
+
+	if err := a.checkEndpoints(ctx, startNode, endNode, true); err != nil {
+		return nil, 0, err
+	}
+	var openEnd *Node
+	if a.config.AllowBlockedEndpoints {
+		openEnd = &endNode
+	}
+
+	cells := a.config.GridWidth * a.config.GridHeight
+	costs := make([]float64, cells)
+	for i := range costs {
+		costs[i] = math.Inf(1)
+	}
+	parents := make([]int, cells)
+	nodes := make([]Node, cells)
+	closed := make([]bool, cells)
+
+	startIndex, _ := a.cellIndex(startNode.X, startNode.Y)
+	startNode.parent = nil
+	costs[startIndex] = 0
+	parents[startIndex] = -1
+	nodes[startIndex] = startNode
+	startH := a.estimateF(startNode, endNode)
+	openList := floatHeap{{index: startIndex, f: a.config.HeuristicWeight * startH, h: startH}}
+
+	timed, _ := ctx.(ITimedContext)
+	var neighbors []Node
+	for openList.Len() > 0 {
+		entry := heap.Pop(&openList).(floatEntry)
+		// a cell is pushed again for every cheaper cost, the first pop is the cheapest
+		if closed[entry.index] {
+			continue
+		}
+		closed[entry.index] = true
+		currentNode := nodes[entry.index]
+
+		if a.IsEndNode(ctx, currentNode, endNode) {
+			return floatPath(nodes, parents, entry.index), costs[entry.index], nil
+		}
+
+		neighbors = a.appendNeighborNodes(ctx, neighbors[:0], &currentNode, openEnd)
+		for _, neighbor := range neighbors {
+			index, ok := a.cellIndex(neighbor.X, neighbor.Y)
+			if !ok || closed[index] {
+				continue
+			}
+			cost := costs[entry.index] + a.floatStepCost(currentNode, neighbor)
+			if parent := parents[entry.index]; a.config.TurnPenalty > 0 && parent >= 0 &&
+				a.stepDelta(nodes[parent], currentNode) != a.stepDelta(currentNode, neighbor) {
+				cost += float64(a.config.TurnPenalty)
+			}
+			if cost >= costs[index] {
+				continue
+			}
+			if timed != nil && timed.IsInBlockAt(neighbor.X, neighbor.Y, int(math.Min(cost, float64(infCost)))) {
+				continue
+			}
+
+			costs[index] = cost
+			parents[index] = entry.index
+			neighbor.parent = nil
+			nodes[index] = neighbor
+			h := a.estimateF(neighbor, endNode)
+			heap.Push(&openList, floatEntry{index: index, f: cost + a.config.HeuristicWeight*h, h: h})
+		}
+	}
+
+	return nil, 0, ErrorNoPath
+}
+
+// floatStepCost is stepCost with the exact length of the step,
+// +Inf if the neighbor cannot be entered
+func (a *PathFinderF) floatStepCost(from, to Node) float64 {
+	var cost float64
+	if a.config.MoveCost != nil {
+		moveCost := a.config.MoveCost(from, to)
+		if moveCost < 0 {
+			moveCost = 0
+		}
+		cost = float64(moveCost)
+	} else {
+		enterCost := a.enterCost(to.X, to.Y)
+		if enterCost >= infCost {
+			return math.Inf(1)
+		}
+		cost = a.stepLength(from, to) + float64(enterCost)
+	}
+	if cost >= float64(infCost) || to.Weighting >= infCost {
+		return math.Inf(1)
+	}
+	return cost + float64(to.Weighting)
+}
+
+// stepLength returns the euclidean length of the step in the cell sizes,
+// scaled by OrthogonalCost. A hex step has the length 1
+func (a *PathFinderF) stepLength(from, to Node) float64 {
+	if a.config.GridType == GridHex {
+		return float64(a.config.OrthogonalCost)
+	}
+	width, height := a.cellSize()
+	delta := a.stepDelta(from, to)
+	return math.Hypot(float64(delta[0])*width, float64(delta[1])*height) * float64(a.config.OrthogonalCost)
+}
+
+// estimateF returns the float heuristic, 0 with DisableHeuristic
+func (a *PathFinderF) estimateF(node, endNode Node) float64 {
+	if a.config.DisableHeuristic {
+		return 0
+	}
+	return a.floatH(node, endNode)
+}
+
+// floatH returns the cost of the cheapest free path between two nodes,
+// a custom Heuristic is scaled by OrthogonalCost like in the int mode
+func (a *PathFinderF) floatH(nodeA, nodeB Node) float64 {
+	if a.config.Heuristic != nil || a.config.GridType == GridHex {
+		return float64(a.H(nodeA, nodeB)) * float64(a.config.OrthogonalCost)
+	}
+
+	dx, dy := absInt(nodeB.X-nodeA.X), absInt(nodeB.Y-nodeA.Y)
+	if a.config.WrapEdges {
+		// the distance grows with both offsets, so the nearest copy is the nearest on each axis
+		if other := a.config.GridWidth - dx; other < dx {
+			dx = other
+		}
+		if other := a.config.GridHeight - dy; other < dy {
+			dy = other
+		}
+	}
+	width, height := a.cellSize()
+	straightX, straightY := float64(dx)*width, float64(dy)*height
+	if a.config.AllowDiagonal {
+		diagonal := dx
+		if dy < diagonal {
+			diagonal = dy
+		}
+		straightX = float64(dx-diagonal) * width
+		straightY = float64(dy-diagonal) * height
+		return (straightX + straightY + float64(diagonal)*math.Hypot(width, height)) * float64(a.config.OrthogonalCost)
+	}
+	return (straightX + straightY) * float64(a.config.OrthogonalCost)
+}
+
+// cellSize returns CellWidth and CellHeight, 0 means 1
+func (a *PathFinderF) cellSize() (float64, float64) {
+	width, height := a.config.CellWidth, a.config.CellHeight
+	if width == 0 {
+		width = 1
+	}
+	if height == 0 {
+		height = 1
+	}
+	return width, height
+}
+
+// floatPath returns the nodes from the start to the cell index in start to goal order
+func floatPath(nodes []Node, parents []int, index int) []Node {
+	var nodePath []Node
+	for ; index >= 0; index = parents[index] {
+		nodePath = append(nodePath, nodes[index])
+	}
+	for i, j := 0, len(nodePath)-1; i < j; i, j = i+1, j-1 {
+		nodePath[i], nodePath[j] = nodePath[j], nodePath[i]
+	}
+	return nodePath
+}
+
+// floatEntry is a cell in the open list of PathFinderF
+type floatEntry struct {
+	index int     // 格子索引
+	f, h  float64 // 排序用的代价
+}
+
+// floatHeap is a min heap of floatEntry ordered by F, then H and then the cell index
+type floatHeap []floatEntry
+
+func (h floatHeap) Len() int { return len(h) }
+func (h floatHeap) Less(i, j int) bool {
+	if h[i].f != h[j].f {
+		return h[i].f < h[j].f
+	}
+	if h[i].h != h[j].h {
+		return h[i].h < h[j].h
+	}
+	return h[i].index < h[j].index
+}
+func (h floatHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *floatHeap) Push(x interface{}) { *h = append(*h, x.(floatEntry)) }
+func (h *floatHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
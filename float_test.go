@@ -0,0 +1,103 @@
+package astar
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestAstar_FindPathFloatDiagonal(t *testing.T) {
+	// . . . E
+	// . . . .
+	// . . . .
+	// S . . .
+	a, err := NewF(Config{GridWidth: 4, GridHeight: 4, AllowDiagonal: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+
+	foundPath, cost, err := a.FindPathWithCost(nil, Node{X: 0, Y: 0}, Node{X: 3, Y: 3})
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if len(foundPath) != 4 {
+		t.Error("the path should be the diagonal: ", foundPath)
+	}
+	// the int mode rounds every diagonal step to 14
+	if want := 30 * math.Sqrt2; math.Abs(cost-want) > 1e-9 {
+		t.Error("the cost should be three exact diagonal steps: ", cost, want)
+	}
+	_, intCost, err := a.PathFinder.FindPathWithCost(nil, Node{X: 0, Y: 0}, Node{X: 3, Y: 3})
+	if err != nil || intCost != 42 {
+		t.Error("the embedded PathFinder should keep the int costs: ", intCost, err)
+	}
+}
+
+func TestAstar_FindPathFloatMatchesInt(t *testing.T) {
+	// without diagonals all step costs are integers, both modes find the same cost
+	rnd := rand.New(rand.NewSource(3))
+	for round := 0; round < 20; round++ {
+		var invalidNodes, weightedNodes []Node
+		for x := 0; x < 15; x++ {
+			for y := 0; y < 15; y++ {
+				switch rnd.Intn(6) {
+				case 0:
+					invalidNodes = append(invalidNodes, Node{X: x, Y: y})
+				case 1:
+					weightedNodes = append(weightedNodes, Node{X: x, Y: y, Weighting: rnd.Intn(5)})
+				}
+			}
+		}
+		config := Config{GridWidth: 15, GridHeight: 15, InvalidNodes: invalidNodes, WeightedNodes: weightedNodes}
+		a, err := New(config)
+		if err != nil {
+			t.Fatal("there should be no error", err)
+		}
+		f, err := NewF(config)
+		if err != nil {
+			t.Fatal("there should be no error", err)
+		}
+
+		startNode, endNode := Node{X: 0, Y: 0}, Node{X: 14, Y: 14}
+		a.RemoveObstacle(startNode.X, startNode.Y)
+		f.RemoveObstacle(startNode.X, startNode.Y)
+		a.RemoveObstacle(endNode.X, endNode.Y)
+		f.RemoveObstacle(endNode.X, endNode.Y)
+
+		_, intCost, intErr := a.FindPathWithCost(nil, startNode, endNode)
+		floatPath, floatCost, floatErr := f.FindPathWithCost(nil, startNode, endNode)
+		if intErr != floatErr {
+			t.Fatal("both modes should agree on the path: ", intErr, floatErr)
+		}
+		if intErr != nil {
+			continue
+		}
+		if float64(intCost) != floatCost {
+			t.Error("both modes should find the same cost: ", intCost, floatCost)
+		}
+		if floatPath[0] != startNode || floatPath[len(floatPath)-1] != endNode {
+			t.Error("the path should lead from the start to the end node: ", floatPath)
+		}
+	}
+}
+
+func TestAstar_FindPathFloatCellSize(t *testing.T) {
+	a, err := NewF(Config{GridWidth: 5, GridHeight: 5, AllowDiagonal: true, CellWidth: 2, CellHeight: 1})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+
+	_, cost, err := a.FindPathWithCost(nil, Node{X: 0, Y: 0}, Node{X: 4, Y: 1})
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	// one diagonal of sqrt(5) and three steps of width 2
+	if want := 10 * (math.Sqrt(5) + 6); math.Abs(cost-want) > 1e-9 {
+		t.Error("the cost should follow the cell sizes: ", cost, want)
+	}
+
+	a.AddObstacle(2, 2)
+	if _, _, err := a.FindPathWithCost(nil, Node{X: 0, Y: 0}, Node{X: 2, Y: 2}); err != ErrEndBlocked {
+		t.Error("a blocked end node should be an error: ", err)
+	}
+}
@@ -0,0 +1,135 @@
+package astar
+
+// FindPathFringe starts the Fringe Search for the given start and end node
+// The return value has the same format as FindPath
+//
+// Fringe Search keeps the open nodes in an unsorted list instead of a heap and walks it
+// again and again with a growing F limit: the nodes within the limit are expanded, their
+// children are visited next in the same pass and the others wait for the next limit.
+// It expands nodes more than once but a node only costs one cell of the list, about half the memory
+// of a single FindPath, which however reuses its pooled storage for the following searches.
+// With an admissible heuristic the path is as short as the one of FindPath. With TurnPenalty set or an ITimedContext
+// it falls back to FindPath, MaxOpenNodes, BucketOpenList and OpenListFactory are not used
+func (a *PathFinder) FindPathFringe(ctx IContext, startNode, endNode Node) ([]Node, error) {
+	if a.config.TurnPenalty > 0 || isTimed(ctx) {
+		return a.FindPath(ctx, startNode, endNode)
+	}
+
+	a.obstacleMu.RLock()
+	defer a.obstacleMu.RUnlock()
+	endNode = a.snapEnd(ctx, endNode)
+
+	if err := a.checkEndpoints(ctx, startNode, endNode, true); err != nil {
+		return nil, err
+	}
+	var openEnd *Node
+	if a.config.AllowBlockedEndpoints {
+		openEnd = &endNode
+	}
+	estimate := func(node *Node) {
+		node.h = 0
+		if !a.config.DisableHeuristic {
+			node.h = a.hCost(*node, endNode)
+		}
+		node.f = addCost(node.g, a.weightH(node.h))
+	}
+
+	// the fringe is a ring with a sentinel, the cache keeps the best G of every visited cell
+	fringe := &fringeCell{}
+	fringe.prev, fringe.next = fringe, fringe
+	startNode.parent, startNode.g = nil, 0
+	estimate(&startNode)
+	start := &fringeCell{node: startNode}
+	fringe.insertAfter(start)
+	cache := map[[2]int]*fringeCell{nodeKey(startNode): start}
+	// the cells are allocated in blocks, one allocation per cell would dominate the search
+	var block []fringeCell
+
+	limit := startNode.f
+	var neighbors []Node
+	for fringe.next != fringe {
+		nextLimit := infCost
+		for cell := fringe.next; cell != fringe; {
+			if cell.node.f > limit {
+				if cell.node.f < nextLimit {
+					nextLimit = cell.node.f
+				}
+				cell = cell.next
+				continue
+			}
+			if a.IsEndNode(ctx, cell.node, endNode) {
+				return cell.path(), nil
+			}
+
+			neighbors = a.appendNeighborNodes(ctx, neighbors[:0], &cell.node, openEnd)
+			// the children are inserted behind the cell, the first neighbor ends up first
+			for i := len(neighbors) - 1; i >= 0; i-- {
+				neighbor := neighbors[i]
+				neighbor.g = addCost(cell.node.g, a.stepCost(cell.node, neighbor))
+				if neighbor.g >= infCost {
+					continue
+				}
+				child, ok := cache[nodeKey(neighbor)]
+				if ok && neighbor.g >= child.node.g {
+					continue
+				}
+				if !ok {
+					if len(block) == 0 {
+						block = make([]fringeCell, fringeBlockSize)
+					}
+					child, block = &block[0], block[1:]
+					cache[nodeKey(neighbor)] = child
+				} else if child.next != nil {
+					child.unlink()
+				}
+				neighbor.parent = nil
+				estimate(&neighbor)
+				child.node, child.parent = neighbor, cell
+				cell.insertAfter(child)
+			}
+
+			next := cell.next
+			cell.unlink()
+			cell = next
+		}
+		limit = nextLimit
+	}
+
+	return nil, ErrorNoPath
+}
+
+// fringeBlockSize is the number of fringeCells FindPathFringe allocates at once
+const fringeBlockSize = 256
+
+// fringeCell is a cell of FindPathFringe, next is nil while it is not in the fringe
+type fringeCell struct {
+	node       Node
+	parent     *fringeCell // 当前最优路径上的前一个格子
+	prev, next *fringeCell
+}
+
+// insertAfter links cell into the fringe behind c
+func (c *fringeCell) insertAfter(cell *fringeCell) {
+	cell.prev, cell.next = c, c.next
+	c.next.prev = cell
+	c.next = cell
+}
+
+// unlink removes the cell from the fringe
+func (c *fringeCell) unlink() {
+	c.prev.next = c.next
+	c.next.prev = c.prev
+	c.prev, c.next = nil, nil
+}
+
+// path returns the nodes from the start to the cell in start to goal order
+func (c *fringeCell) path() []Node {
+	var nodePath []Node
+	for cell := c; cell != nil; cell = cell.parent {
+		nodePath = append(nodePath, cell.node)
+	}
+	for i, j := 0, len(nodePath)-1; i < j; i, j = i+1, j-1 {
+		nodePath[i], nodePath[j] = nodePath[j], nodePath[i]
+	}
+	return nodePath
+}
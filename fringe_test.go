@@ -0,0 +1,70 @@
+package astar
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestAstar_FindPathFringe(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [E]   S: StartNode
+	// [ ] [ ] [ ] [ ] [ ]   E: EndNode
+	// [ ] [ ] [O] [O] [O]   O: ObstacleNode
+	// [ ] [ ] [ ] [ ] [ ]
+	// [S] [ ] [ ] [ ] [ ]
+
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 4, Y: 4}
+	a, err := New(Config{GridWidth: 5, GridHeight: 5, InvalidNodes: []Node{{X: 2, Y: 2}, {X: 3, Y: 2}, {X: 4, Y: 2}}})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, err := a.FindPathFringe(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if len(foundPath) != 9 || foundPath[0].X != startNode.X || foundPath[8].X != endNode.X || foundPath[8].Y != endNode.Y {
+		t.Error("the path should lead around the wall: ", foundPath)
+	}
+
+	a.AddObstacle(1, 2)
+	a.AddObstacle(0, 2)
+	if _, err = a.FindPathFringe(nil, startNode, endNode); err != ErrorNoPath {
+		t.Error("the wall should split the grid", err)
+	}
+}
+
+func TestAstar_FindPathFringeCost(t *testing.T) {
+	// the path costs match FindPath on random weighted maps
+	rnd := rand.New(rand.NewSource(7))
+	for round := 0; round < 30; round++ {
+		var invalidNodes, weightedNodes []Node
+		for x := 0; x < 16; x++ {
+			for y := 0; y < 16; y++ {
+				if (x == 0 && y == 0) || (x == 15 && y == 15) {
+					continue
+				}
+				switch rnd.Intn(5) {
+				case 0:
+					invalidNodes = append(invalidNodes, Node{X: x, Y: y})
+				case 1:
+					weightedNodes = append(weightedNodes, Node{X: x, Y: y, Weighting: rnd.Intn(20)})
+				}
+			}
+		}
+		a, err := New(Config{GridWidth: 16, GridHeight: 16, InvalidNodes: invalidNodes, WeightedNodes: weightedNodes, AllowDiagonal: round%2 == 0})
+		if err != nil {
+			t.Fatal("there should be no error", err)
+		}
+
+		startNode, endNode := Node{X: 0, Y: 0}, Node{X: 15, Y: 15}
+		wantPath, wantErr := a.FindPath(nil, startNode, endNode)
+		foundPath, err := a.FindPathFringe(nil, startNode, endNode)
+		if err != wantErr {
+			t.Fatal("both searches should agree on the path: ", err, wantErr)
+		}
+		if err == nil && foundPath[len(foundPath)-1].g != wantPath[len(wantPath)-1].g {
+			t.Error("the fringe path should cost the same: ", foundPath[len(foundPath)-1].g, wantPath[len(wantPath)-1].g)
+		}
+	}
+}
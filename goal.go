@@ -0,0 +1,65 @@
+package astar
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// GoalSearcher runs the searches of a PathFinder to one fixed goal,
+// e.g. for all units chasing the player
+//
+// With a custom Heuristic the H of every cell is computed once and kept for
+// the following searches, the default heuristics are cheaper than the lookup
+// and are not cached. The cache belongs to the config of the PathFinder,
+// create a new GoalSearcher after Reconfigure. It is safe for concurrent use
+type GoalSearcher struct {
+	finder *PathFinder
+	goal   Node
+	cache  []int64             // 每个格子的H+1, 0表示还没有计算
+	goalH  func(node Node) int // 传给搜索的H, 默认启发函数时为nil
+}
+
+// WithGoal returns a GoalSearcher for searches from any start node to goal
+func (a *PathFinder) WithGoal(goal Node) *GoalSearcher {
+	g := &GoalSearcher{finder: a, goal: goal}
+	if a.config.Heuristic != nil {
+		g.cache = make([]int64, a.config.GridWidth*a.config.GridHeight)
+		g.goalH = g.cachedH
+	}
+	return g
+}
+
+// Goal returns the goal of the searches
+func (g *GoalSearcher) Goal() Node {
+	return g.goal
+}
+
+// FindPath works like PathFinder.FindPath from startNode to the goal
+func (g *GoalSearcher) FindPath(ctx IContext, startNode Node) ([]Node, error) {
+	return g.finder.doFindPath(context.Background(), ctx, startNode, g.goal, searchOptions{maxSteps: StepsNoLimit, goalH: g.goalH})
+}
+
+// FindPathWithCost works like PathFinder.FindPathWithCost from startNode to the goal
+func (g *GoalSearcher) FindPathWithCost(ctx IContext, startNode Node) ([]Node, int, error) {
+	foundPath, err := g.FindPath(ctx, startNode)
+	if err != nil {
+		return nil, 0, err
+	}
+	return foundPath, foundPath[len(foundPath)-1].g, nil
+}
+
+// cachedH returns the hCost of the node to the goal and computes it only once per cell
+func (g *GoalSearcher) cachedH(node Node) int {
+	index, ok := g.finder.cellIndex(node.X, node.Y)
+	if !ok || index >= len(g.cache) {
+		return g.finder.hCost(node, g.goal)
+	}
+	if cached := atomic.LoadInt64(&g.cache[index]); cached > 0 {
+		return int(cached - 1)
+	}
+	h := g.finder.hCost(node, g.goal)
+	if h < infCost {
+		atomic.StoreInt64(&g.cache[index], int64(h)+1)
+	}
+	return h
+}
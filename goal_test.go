@@ -0,0 +1,52 @@
+package astar
+
+import "testing"
+
+func TestAstar_WithGoal(t *testing.T) {
+	// . . . . E
+	// . X X X .
+	// . . . X .
+	// S . . X .
+	calls := 0
+	a, err := New(Config{
+		GridWidth:    5,
+		GridHeight:   4,
+		InvalidNodes: []Node{{X: 3, Y: 0}, {X: 3, Y: 1}, {X: 1, Y: 2}, {X: 2, Y: 2}, {X: 3, Y: 2}},
+		Heuristic: func(nodeA, nodeB Node) int {
+			calls++
+			return ManhattanDistance(nodeA, nodeB)
+		},
+	})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	endNode := Node{X: 4, Y: 3}
+	searcher := a.WithGoal(endNode)
+	if searcher.Goal() != endNode {
+		t.Error("the searcher should keep its goal: ", searcher.Goal())
+	}
+
+	wantPath, wantCost, err := a.FindPathWithCost(nil, Node{X: 0, Y: 0}, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	foundPath, cost, err := searcher.FindPathWithCost(nil, Node{X: 0, Y: 0})
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if cost != wantCost || len(foundPath) != len(wantPath) {
+		t.Error("the searcher should find the path of FindPath: ", foundPath, wantPath)
+	}
+
+	// the second search finds every H in the cache
+	calls = 0
+	if _, err := searcher.FindPath(nil, Node{X: 0, Y: 0}); err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if calls != 0 {
+		t.Error("the heuristic should not be called again: ", calls)
+	}
+	if _, err := searcher.FindPath(nil, Node{X: 3, Y: 1}); err != ErrStartBlocked {
+		t.Error("a blocked start node should be an error: ", err)
+	}
+}
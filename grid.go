@@ -0,0 +1,41 @@
+package astar
+
+import (
+	"errors"
+	"fmt"
+)
+
+// GridWall marks a not accessible cell in the slice of ConfigFromGrid
+const GridWall = -1
+
+// ConfigFromGrid creates a Config from rows of cell values
+// 0 is walkable, GridWall is an obstacle and positive values are the weighting of the cell
+//
+// The first row is the top of the grid like it is written in the source,
+// so grid[len(grid)-1][0] becomes the node X:0 / Y:0
+func ConfigFromGrid(grid [][]int) (Config, error) {
+	height := len(grid)
+	if height < 1 || len(grid[0]) < 1 {
+		return Config{}, errors.New("grid must be min 1x1 cells")
+	}
+	width := len(grid[0])
+
+	config := Config{GridWidth: width, GridHeight: height}
+	for row, cells := range grid {
+		if len(cells) != width {
+			return Config{}, fmt.Errorf("row %d has %d cells, expected %d", row, len(cells), width)
+		}
+		y := height - 1 - row
+		for x, value := range cells {
+			switch {
+			case value == GridWall:
+				config.InvalidNodes = append(config.InvalidNodes, Node{X: x, Y: y})
+			case value > 0:
+				config.WeightedNodes = append(config.WeightedNodes, Node{X: x, Y: y, Weighting: value})
+			case value < 0:
+				return Config{}, fmt.Errorf("invalid cell value %d at row %d column %d", value, row, x)
+			}
+		}
+	}
+	return config, nil
+}
@@ -0,0 +1,55 @@
+package astar
+
+import "testing"
+
+func TestConfigFromGrid(t *testing.T) {
+	config, err := ConfigFromGrid([][]int{
+		{0, 0, 0, 0},
+		{0, -1, 5, 0},
+		{0, 0, 0, 0},
+	})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if config.GridWidth != 4 || config.GridHeight != 3 {
+		t.Error("grid should be 4x3: ", config.GridWidth, config.GridHeight)
+	}
+	if len(config.InvalidNodes) != 1 || config.InvalidNodes[0].X != 1 || config.InvalidNodes[0].Y != 1 {
+		t.Error("wall should be at 1/1: ", config.InvalidNodes)
+	}
+	if len(config.WeightedNodes) != 1 || config.WeightedNodes[0].X != 2 || config.WeightedNodes[0].Weighting != 5 {
+		t.Error("weighted node should be at 2/1: ", config.WeightedNodes)
+	}
+
+	// the first row is the top of the grid
+	config, err = ConfigFromGrid([][]int{
+		{-1, 0},
+		{0, 0},
+	})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if config.InvalidNodes[0].X != 0 || config.InvalidNodes[0].Y != 1 {
+		t.Error("wall should be top left: ", config.InvalidNodes)
+	}
+	a, err := New(config)
+	if err != nil {
+		t.Fatal("config should be valid", err)
+	}
+	if foundPath, err := a.FindPath(nil, Node{X: 0, Y: 0}, Node{X: 1, Y: 1}); err != nil || len(foundPath) != 3 {
+		t.Error("there should be a path around the wall", err, foundPath)
+	}
+
+	if _, err = ConfigFromGrid([][]int{{0, 0}, {0}}); err == nil {
+		t.Error("jagged grid should be invalid")
+	}
+	if _, err = ConfigFromGrid([][]int{{0, 0}, {0, -2}}); err == nil {
+		t.Error("negative values other than GridWall should be invalid")
+	}
+	if _, err = ConfigFromGrid([][]int{{}}); err == nil {
+		t.Error("too small grid should be invalid")
+	}
+	if config, err := ConfigFromGrid([][]int{{0, 0, GridWall}}); err != nil || config.GridHeight != 1 {
+		t.Error("a single row should be a valid corridor", err, config)
+	}
+}
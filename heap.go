@@ -0,0 +1,208 @@
+package astar
+
+import "container/heap"
+
+// Heap represents a priority queue of nodes
+// ordered by the smallest node.F value, by default node.H breaks ties.
+// The remaining ties are ordered by the smaller X and then the smaller Y,
+// so the same nodes always leave the heap in the same order
+//
+// a node is stored at most once for its coordinates,
+// the position of every node is tracked so Remove and Update are O(log n)
+type Heap struct {
+	items heapItems
+}
+
+// NewHeap creates a new heap
+func NewHeap() *Heap {
+	return &Heap{}
+}
+
+// Add one or more nodes to the heap
+// if a node with the same coordinates is already stored it gets replaced
+func (h *Heap) Add(nodes ...Node) {
+	if h.items.indices == nil {
+		h.items.indices = make(map[[2]int]int)
+	}
+	for _, node := range nodes {
+		if index, ok := h.items.indices[nodeKey(node)]; ok {
+			h.items.nodes[index] = node
+			heap.Fix(&h.items, index)
+			continue
+		}
+		// append and fix instead of heap.Push, boxing the node into an interface allocates
+		h.items.Push(node)
+		heap.Fix(&h.items, h.items.Len()-1)
+	}
+}
+
+// All returns the nodes of the heap in no particular order
+func (h *Heap) All() []Node {
+	return h.items.nodes
+}
+
+// Get returns the stored node with the coordinates of searchNode
+func (h *Heap) Get(searchNode Node) (Node, bool) {
+	index, ok := h.items.indices[nodeKey(searchNode)]
+	if !ok {
+		return Node{}, false
+	}
+	return h.items.nodes[index], true
+}
+
+// Update replaces the node with the same coordinates by updateNode
+// if the node is not found we do nothing
+func (h *Heap) Update(updateNode Node) {
+	if index, ok := h.items.indices[nodeKey(updateNode)]; ok {
+		h.items.nodes[index] = updateNode
+		heap.Fix(&h.items, index)
+	}
+}
+
+// Remove a node from the heap
+// if the node is not found we do nothing
+func (h *Heap) Remove(removeNode Node) {
+	if index, ok := h.items.indices[nodeKey(removeNode)]; ok {
+		last := h.items.Len() - 1
+		if index != last {
+			h.items.Swap(index, last)
+		}
+		h.items.nodes = h.items.nodes[:last]
+		delete(h.items.indices, nodeKey(removeNode))
+		if index != last {
+			heap.Fix(&h.items, index)
+		}
+	}
+}
+
+// Contains check if a node is in the heap
+func (h *Heap) Contains(searchNode Node) bool {
+	_, ok := h.items.indices[nodeKey(searchNode)]
+	return ok
+}
+
+// Len returns the number of nodes in the heap
+func (h *Heap) Len() int {
+	return len(h.items.nodes)
+}
+
+// IsEmpty returns if the heap has nodes or not
+func (h *Heap) IsEmpty() bool {
+	return len(h.items.nodes) == 0
+}
+
+// Clear removes all nodes from the heap
+// the allocated storage is kept so the heap can be filled again without allocations
+func (h *Heap) Clear() {
+	h.items.nodes = h.items.nodes[:0]
+	for key := range h.items.indices {
+		delete(h.items.indices, key)
+	}
+}
+
+// GetMinFNode returns the node with the smallest node.F value
+// if the heap is empty it returns ErrEmptyList
+func (h *Heap) GetMinFNode() (Node, error) {
+	if h.IsEmpty() {
+		return Node{}, ErrEmptyList
+	}
+	return h.items.nodes[0], nil
+}
+
+// nodeKey returns the map key of the node coordinates,
+// both coordinates are kept so negative ones cannot collide like in y*width+x
+func nodeKey(node Node) [2]int {
+	return [2]int{node.X, node.Y}
+}
+
+// heapItems implements heap.Interface
+// and keeps indices in sync with the node positions
+type heapItems struct {
+	nodes       []Node
+	indices     map[[2]int]int
+	tieBreaking TieBreaking
+}
+
+func (items heapItems) Len() int {
+	return len(items.nodes)
+}
+
+func (items heapItems) Less(i, j int) bool {
+	nodeA, nodeB := &items.nodes[i], &items.nodes[j]
+	if nodeA.f != nodeB.f {
+		return nodeA.f < nodeB.f
+	}
+	switch items.tieBreaking {
+	case TieBreakNone:
+	case TieBreakCrossProduct:
+		if nodeA.h != nodeB.h {
+			return nodeA.h < nodeB.h
+		}
+		if nodeA.tie != nodeB.tie {
+			return nodeA.tie < nodeB.tie
+		}
+	default:
+		if nodeA.h != nodeB.h {
+			return nodeA.h < nodeB.h
+		}
+	}
+	// the coordinates make the order total, equal inputs expand the same nodes
+	if nodeA.X != nodeB.X {
+		return nodeA.X < nodeB.X
+	}
+	return nodeA.Y < nodeB.Y
+}
+
+func (items heapItems) Swap(i, j int) {
+	items.nodes[i], items.nodes[j] = items.nodes[j], items.nodes[i]
+	items.indices[nodeKey(items.nodes[i])] = i
+	items.indices[nodeKey(items.nodes[j])] = j
+}
+
+func (items *heapItems) Push(x interface{}) {
+	node := x.(Node)
+	items.indices[nodeKey(node)] = len(items.nodes)
+	items.nodes = append(items.nodes, node)
+}
+
+func (items *heapItems) Pop() interface{} {
+	last := len(items.nodes) - 1
+	node := items.nodes[last]
+	items.nodes = items.nodes[:last]
+	delete(items.indices, nodeKey(node))
+	return node
+}
+
+// push, get, update, popMin, size, all and clear let a search use the heap as its openSet
+
+func (h *Heap) push(node Node) {
+	h.Add(node)
+}
+
+func (h *Heap) get(node Node) (Node, bool) {
+	return h.Get(node)
+}
+
+func (h *Heap) update(node Node) {
+	h.Update(node)
+}
+
+func (h *Heap) popMin() (Node, error) {
+	node, err := h.GetMinFNode()
+	if err == nil {
+		h.Remove(node)
+	}
+	return node, err
+}
+
+func (h *Heap) size() int {
+	return h.Len()
+}
+
+func (h *Heap) all() []Node {
+	return append([]Node(nil), h.All()...)
+}
+
+func (h *Heap) clear() {
+	h.Clear()
+}
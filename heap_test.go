@@ -0,0 +1,142 @@
+package astar
+
+import "testing"
+
+func TestHeap_Add(t *testing.T) {
+	nodeA := Node{X: 0, Y: 0, f: 3}
+	nodeB := Node{X: 0, Y: 1, f: 2}
+
+	h := NewHeap()
+
+	h.Add(nodeA)
+
+	if h.Len() != 1 {
+		t.Error("should have a node")
+	}
+
+	h.Add(nodeB)
+
+	if h.Len() != 2 {
+		t.Error("should have a 2 nodes")
+	}
+
+	// same coordinates replace the stored node
+	h.Add(Node{X: 0, Y: 0, f: 1})
+	if h.Len() != 2 {
+		t.Error("should still have 2 nodes")
+	}
+	if node, _ := h.GetMinFNode(); node.X != 0 || node.Y != 0 {
+		t.Error("replaced node should be the min node", node)
+	}
+}
+
+func TestHeap_ContainsRemove(t *testing.T) {
+	nodeA := Node{X: 0, Y: 0}
+	nodeB := Node{X: 0, Y: 1, parent: &nodeA}
+
+	h := NewHeap()
+
+	if h.Contains(nodeA) {
+		t.Error("empty heap should not have nodeA")
+	}
+
+	h.Add(nodeB, nodeA)
+
+	if !h.Contains(nodeA) || !h.Contains(nodeB) {
+		t.Error("should have nodeA and nodeB")
+	}
+
+	h.Remove(nodeA)
+
+	if h.Contains(nodeA) {
+		t.Error("nodeA should not exist")
+	}
+	if !h.Contains(nodeB) {
+		t.Error("nodeB should still be there")
+	}
+
+	h.Remove(nodeB)
+	h.Remove(nodeB) // try removing it twice
+	if !h.IsEmpty() {
+		t.Error("IsEmpty should be true")
+	}
+}
+
+func TestHeap_Update(t *testing.T) {
+	nodeA := Node{X: 1, Y: 0, f: 2}
+	nodeB := Node{X: 2, Y: 2, f: 3}
+	nodeC := Node{X: 2, Y: 3, f: 4}
+
+	h := NewHeap()
+	h.Add(nodeA, nodeB, nodeC)
+
+	h.Update(Node{X: 2, Y: 3, f: 1})
+
+	node, err := h.GetMinFNode()
+	if err != nil {
+		t.Error("There should be no error", err)
+	}
+	if node.X != nodeC.X || node.Y != nodeC.Y {
+		t.Error("nodeC should be the min node now", node)
+	}
+
+	stored, ok := h.Get(nodeC)
+	if !ok || stored.f != 1 {
+		t.Error("nodeC should be updated", stored)
+	}
+
+	// not in the heap, nothing happens
+	h.Update(Node{X: 4, Y: 4})
+	if h.Contains(Node{X: 4, Y: 4}) {
+		t.Error("unknown node should not be added")
+	}
+}
+
+func TestHeap_GetMinFNode(t *testing.T) {
+	h := NewHeap()
+
+	if _, err := h.GetMinFNode(); err != ErrEmptyList {
+		t.Error("we should have ErrEmptyList here", err)
+	}
+
+	// equal F, the lower H wins
+	h.Add(
+		Node{X: 0, Y: 0, f: 5, h: 3},
+		Node{X: 1, Y: 0, f: 4, h: 2},
+		Node{X: 2, Y: 0, f: 4, h: 1},
+		Node{X: 3, Y: 0, f: 6, h: 0},
+	)
+
+	wantX := []int{2, 1, 0, 3}
+	for _, x := range wantX {
+		node, err := h.GetMinFNode()
+		if err != nil {
+			t.Fatal("There should be no error", err)
+		}
+		if node.X != x {
+			t.Error("unexpected min node", node)
+		}
+		h.Remove(node)
+	}
+
+	h.Add(Node{X: 0, Y: 0})
+	h.Clear()
+	if !h.IsEmpty() || h.Contains(Node{X: 0, Y: 0}) {
+		t.Error("IsEmpty should be true")
+	}
+}
+
+func TestHeap_TieOrder(t *testing.T) {
+	h := NewHeap()
+	h.items.tieBreaking = TieBreakNone
+	h.Add(Node{X: 2, Y: 1, f: 5, h: 1}, Node{X: 1, Y: 1, f: 5, h: 3}, Node{X: 1, Y: 0, f: 5, h: 2})
+
+	// equal F, ordered by X and then Y
+	for _, want := range []Node{{X: 1, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 1}} {
+		node, _ := h.GetMinFNode()
+		if node.X != want.X || node.Y != want.Y {
+			t.Error("wrong node order: ", node, want)
+		}
+		h.Remove(node)
+	}
+}
@@ -0,0 +1,75 @@
+package astar
+
+import "math"
+
+// Heuristic estimates the distance between two nodes in grid steps
+// the finder scales the value with the cost of an orthogonal step
+//
+// The built-in heuristics use integer math only, they are exact as long as
+// the X and Y distance between two nodes stays below math.MaxInt32 on 64-bit platforms
+type Heuristic func(nodeA, nodeB Node) int
+
+// ManhattanDistance returns the sum of the absolute X and Y distances
+// suitable for 4-directional movement
+func ManhattanDistance(nodeA, nodeB Node) int {
+	return absInt(nodeA.X-nodeB.X) + absInt(nodeA.Y-nodeB.Y)
+}
+
+// EuclideanDistance returns the rounded straight line distance
+// It is not admissible with the default costs of 10 and 14 for 8-directional movement:
+// the rounding and a diagonal of sqrt(2) instead of 1.4 can exceed the cost of the path,
+// e.g. it returns 10 for 7/7 where the path costs 98, H is 100 then
+func EuclideanDistance(nodeA, nodeB Node) int {
+	dx := absInt(nodeA.X - nodeB.X)
+	dy := absInt(nodeA.Y - nodeB.Y)
+	return roundSqrt(dx*dx + dy*dy)
+}
+
+// ChebyshevDistance returns the larger of the absolute X and Y distances
+// suitable for 8-directional movement where diagonals cost the same as orthogonal steps
+func ChebyshevDistance(nodeA, nodeB Node) int {
+	absX := absInt(nodeA.X - nodeB.X)
+	absY := absInt(nodeA.Y - nodeB.Y)
+	if absX > absY {
+		return absX
+	}
+	return absY
+}
+
+// OctileDistance returns the distance for 8-directional movement where a diagonal step
+// costs about sqrt(2), rounded down. A diagonal step counts 1.4 like the default costs
+// of 10 and 14, so H stays admissible for them as well as for exact sqrt(2) steps
+func OctileDistance(nodeA, nodeB Node) int {
+	absX := absInt(nodeA.X - nodeB.X)
+	absY := absInt(nodeA.Y - nodeB.Y)
+	if absX < absY {
+		absX, absY = absY, absX
+	}
+	// max - min straight steps and min diagonal steps of 1.4
+	return absX - absY + absY*7/5
+}
+
+// HexDistance returns the number of steps between two hexagons in axial coordinates
+// it is the default heuristic of GridHex
+func HexDistance(nodeA, nodeB Node) int {
+	dq := nodeA.X - nodeB.X
+	dr := nodeA.Y - nodeB.Y
+	return (absInt(dq) + absInt(dr) + absInt(dq+dr)) / 2
+}
+
+// roundSqrt returns the square root of n >= 0 rounded to the nearest integer
+func roundSqrt(n int) int {
+	// the float estimate is corrected so the result is exact for large n
+	r := int(math.Sqrt(float64(n)))
+	for r*r > n {
+		r--
+	}
+	for n-r*r >= 2*r+1 {
+		r++
+	}
+	// sqrt(n) >= r+0.5 if n > r*r+r
+	if n-r*r > r {
+		r++
+	}
+	return r
+}
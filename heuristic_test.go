@@ -0,0 +1,194 @@
+package astar
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestHeuristics(t *testing.T) {
+	nodeA := Node{X: 0, Y: 0}
+	nodeB := Node{X: 3, Y: 4}
+	nodeC := Node{X: -2, Y: 2}
+
+	if ManhattanDistance(nodeA, nodeB) != 7 {
+		t.Error("should be 7")
+	}
+	if EuclideanDistance(nodeA, nodeB) != 5 {
+		t.Error("should be 5")
+	}
+	if ChebyshevDistance(nodeA, nodeB) != 4 {
+		t.Error("should be 4")
+	}
+	// 1 + 3 * 1.4
+	if OctileDistance(nodeA, nodeB) != 5 {
+		t.Error("should be 5")
+	}
+
+	if ManhattanDistance(nodeA, nodeC) != 4 {
+		t.Error("should be 4")
+	}
+	// sqrt(8)
+	if EuclideanDistance(nodeA, nodeC) != 3 {
+		t.Error("should be 3")
+	}
+	if ChebyshevDistance(nodeA, nodeC) != 2 {
+		t.Error("should be 2")
+	}
+	// 2 * 1.4 rounded down
+	if OctileDistance(nodeA, nodeC) != 2 {
+		t.Error("should be 2")
+	}
+
+	for _, fn := range []Heuristic{ManhattanDistance, EuclideanDistance, ChebyshevDistance, OctileDistance} {
+		if fn(nodeB, nodeB) != 0 {
+			t.Error("distance to itself should be 0")
+		}
+	}
+}
+
+func TestHeuristicsLargeCoordinates(t *testing.T) {
+	if strconv.IntSize < 64 {
+		t.Skip("large coordinates need 64-bit int")
+	}
+	maxCoord := math.MaxInt32
+
+	// small distances far away from the origin
+	nodeA := Node{X: maxCoord, Y: maxCoord}
+	nodeB := Node{X: maxCoord - 3, Y: maxCoord - 4}
+	if ManhattanDistance(nodeA, nodeB) != 7 {
+		t.Error("should be 7")
+	}
+	if EuclideanDistance(nodeA, nodeB) != 5 {
+		t.Error("should be 5")
+	}
+	if ChebyshevDistance(nodeA, nodeB) != 4 {
+		t.Error("should be 4")
+	}
+	if OctileDistance(nodeA, nodeB) != 5 {
+		t.Error("should be 5")
+	}
+
+	// the largest safe distance
+	nodeC := Node{X: 0, Y: 0}
+	if ManhattanDistance(nodeA, nodeC) != 2*maxCoord {
+		t.Error("should be 2*MaxInt32", ManhattanDistance(nodeA, nodeC))
+	}
+	if ChebyshevDistance(nodeA, nodeC) != maxCoord {
+		t.Error("should be MaxInt32", ChebyshevDistance(nodeA, nodeC))
+	}
+	// sqrt(2) * MaxInt32 = 3037000498.56
+	if EuclideanDistance(nodeA, nodeC) != 3037000499 {
+		t.Error("should be 3037000499", EuclideanDistance(nodeA, nodeC))
+	}
+	// 1.4 * MaxInt32 = 3006477105.8
+	if OctileDistance(nodeA, nodeC) != 3006477105 {
+		t.Error("should be 3006477105", OctileDistance(nodeA, nodeC))
+	}
+	// 1 + 1.4 * (MaxInt32 - 1)
+	if OctileDistance(Node{X: maxCoord, Y: maxCoord - 1}, nodeC) != 3006477105 {
+		t.Error("should be 3006477105", OctileDistance(Node{X: maxCoord, Y: maxCoord - 1}, nodeC))
+	}
+
+	a, err := New(Config{GridWidth: 5, GridHeight: 5, OriginX: maxCoord - 4, OriginY: maxCoord - 4})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if a.H(nodeA, nodeB) != 7 {
+		t.Error("should be 7")
+	}
+	if _, err = a.FindPath(nil, nodeB, nodeA); err != nil {
+		t.Error("there should be a path", err)
+	}
+}
+
+func TestHexDistance(t *testing.T) {
+	nodeA := Node{X: 0, Y: 0}
+
+	if HexDistance(nodeA, Node{X: 2, Y: -1}) != 2 {
+		t.Error("should be 2")
+	}
+	if HexDistance(nodeA, Node{X: 3, Y: 3}) != 6 {
+		t.Error("should be 6")
+	}
+	// along the q-r diagonal
+	if HexDistance(nodeA, Node{X: -2, Y: 2}) != 2 {
+		t.Error("should be 2")
+	}
+	if HexDistance(Node{X: 3, Y: 3}, nodeA) != HexDistance(nodeA, Node{X: 3, Y: 3}) {
+		t.Error("distance should be symmetric")
+	}
+}
+
+func TestAstar_FindPathHex(t *testing.T) {
+	a, err := New(Config{GridWidth: 5, GridHeight: 5, GridType: GridHex, AllowDiagonal: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+
+	// every neighbor is one hex step away
+	center := Node{X: 2, Y: 2}
+	neighbors := a.GetNeighborNodes(nil, center)
+	if len(neighbors) != 6 {
+		t.Error("hex should have 6 neighbors: ", neighbors)
+	}
+	for _, neighbor := range neighbors {
+		if HexDistance(center, neighbor) != 1 {
+			t.Error("neighbor should be adjacent: ", neighbor)
+		}
+	}
+	if neighbors = a.GetNeighborNodes(nil, Node{X: 0, Y: 0}); len(neighbors) != 2 {
+		t.Error("corner hex should have 2 neighbors: ", neighbors)
+	}
+	if a.H(Node{X: 0, Y: 4}, Node{X: 4, Y: 0}) != 4 {
+		t.Error("should use the hex distance")
+	}
+
+	// the q-r diagonal is a straight line of hexes
+	foundPath, cost, err := a.FindPathWithCost(nil, Node{X: 0, Y: 4}, Node{X: 4, Y: 0})
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if len(foundPath) != 5 || cost != 4 {
+		t.Error("path should take 4 hex steps: ", cost, foundPath)
+	}
+}
+
+func TestAstar_HConfigured(t *testing.T) {
+	nodeA := Node{X: 0, Y: 0}
+	nodeB := Node{X: 3, Y: 4}
+
+	a, err := New(Config{GridWidth: 6, GridHeight: 6, Heuristic: ChebyshevDistance})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if a.H(nodeA, nodeB) != 4 {
+		t.Error("should be 4")
+	}
+
+	foundPath, err := a.FindPath(nil, nodeA, nodeB)
+	if err != nil || len(foundPath) == 0 {
+		t.Error("there should be a path", err)
+	}
+}
+
+func TestOctileDistanceAdmissible(t *testing.T) {
+	// the default costs of 10 and 14 are below sqrt(2) for the diagonal
+	a, err := New(Config{GridWidth: 100, GridHeight: 100, AllowDiagonal: true, Heuristic: OctileDistance})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	startNode := Node{X: 0, Y: 0}
+	for _, endNode := range []Node{{X: 7, Y: 7}, {X: 99, Y: 99}, {X: 99, Y: 40}, {X: 3, Y: 4}} {
+		_, cost, err := a.FindPathWithCost(nil, startNode, endNode)
+		if err != nil {
+			t.Fatal("there should be a path", err)
+		}
+		if h := a.hCost(startNode, endNode); h > cost {
+			t.Error("H should not exceed the cost: ", endNode, h, cost)
+		}
+	}
+	if _, cost, _ := a.FindPathWithCost(nil, startNode, Node{X: 7, Y: 7}); cost != 98 || a.hCost(startNode, Node{X: 7, Y: 7}) != 90 {
+		t.Error("the diagonal of 7 steps should cost 98 with H 90: ", cost, a.hCost(startNode, Node{X: 7, Y: 7}))
+	}
+}
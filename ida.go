@@ -0,0 +1,119 @@
+package astar
+
+// FindPathIDA starts the Iterative Deepening A* search for the given start and end node
+// The return value has the same format as FindPath
+//
+// IDA* keeps no open and closed list, it runs depth first searches which cut every branch
+// at an F above the limit and raises the limit to the smallest cut F until the goal is reached.
+// It only holds the current path and the neighbors of its nodes, so the memory grows with the
+// length of the path instead of the number of expanded nodes. Without a closed list a cell is
+// expanded again for every way to it, on open grids with many equally long paths it can be
+// slower than FindPath by orders of magnitude, so it suits small or narrow maps.
+// With an admissible heuristic the path is as short as the one of FindPath.
+// With TurnPenalty set or an ITimedContext it falls back to FindPath
+func (a *PathFinder) FindPathIDA(ctx IContext, startNode, endNode Node) ([]Node, error) {
+	if a.config.TurnPenalty > 0 || isTimed(ctx) {
+		return a.FindPath(ctx, startNode, endNode)
+	}
+
+	a.obstacleMu.RLock()
+	defer a.obstacleMu.RUnlock()
+	endNode = a.snapEnd(ctx, endNode)
+
+	if err := a.checkEndpoints(ctx, startNode, endNode, true); err != nil {
+		return nil, err
+	}
+
+	s := &idaSearch{finder: a, ctx: ctx, endNode: endNode}
+	if a.config.AllowBlockedEndpoints {
+		s.openEnd = &s.endNode
+	}
+	startNode.parent, startNode.g = nil, 0
+	s.estimate(&startNode)
+	s.path = append(s.path, startNode)
+	s.limit = startNode.f
+
+	for {
+		nextLimit, found := s.search(0)
+		if found {
+			return append([]Node(nil), s.path...), nil
+		}
+		if nextLimit >= infCost {
+			return nil, ErrorNoPath
+		}
+		s.limit = nextLimit
+	}
+}
+
+// idaSearch holds the state of a single FindPathIDA call
+type idaSearch struct {
+	finder    *PathFinder
+	ctx       IContext
+	endNode   Node
+	openEnd   *Node    // 允许进入的阻挡终点
+	path      []Node   // 当前深度优先搜索的路径
+	neighbors [][]Node // 每层的邻居缓冲区
+	limit     int      // 本轮的F上限
+}
+
+// search expands the last node of the path with a depth first search,
+// it returns the smallest F above the limit if the goal was not found
+func (s *idaSearch) search(depth int) (int, bool) {
+	a := s.finder
+	node := s.path[depth]
+	if node.f > s.limit {
+		return node.f, false
+	}
+	if a.IsEndNode(s.ctx, node, s.endNode) {
+		return 0, true
+	}
+
+	if len(s.neighbors) <= depth {
+		s.neighbors = append(s.neighbors, nil)
+	}
+	s.neighbors[depth] = a.appendNeighborNodes(s.ctx, s.neighbors[depth][:0], &node, s.openEnd)
+
+	minLimit := infCost
+	for _, neighbor := range s.neighbors[depth] {
+		if s.onPath(neighbor) {
+			continue
+		}
+		neighbor.g = addCost(node.g, a.stepCost(node, neighbor))
+		if neighbor.g >= infCost {
+			continue
+		}
+		neighbor.parent = nil
+		s.estimate(&neighbor)
+
+		s.path = append(s.path, neighbor)
+		nextLimit, found := s.search(depth + 1)
+		if found {
+			return 0, true
+		}
+		if nextLimit < minLimit {
+			minLimit = nextLimit
+		}
+		s.path = s.path[:depth+1]
+	}
+	return minLimit, false
+}
+
+// onPath checks if the node is already part of the current path
+func (s *idaSearch) onPath(node Node) bool {
+	for _, pathNode := range s.path {
+		if pathNode.X == node.X && pathNode.Y == node.Y {
+			return true
+		}
+	}
+	return false
+}
+
+// estimate sets H and F of the node like calculateNode
+func (s *idaSearch) estimate(node *Node) {
+	a := s.finder
+	node.h = 0
+	if !a.config.DisableHeuristic {
+		node.h = a.hCost(*node, s.endNode)
+	}
+	node.f = addCost(node.g, a.weightH(node.h))
+}
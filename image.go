@@ -0,0 +1,52 @@
+package astar
+
+import (
+	"errors"
+	"image"
+	"image/color"
+)
+
+// ImageOptions controls how ConfigFromImage reads the pixels
+//
+// The brightness of a pixel is its gray value from 0 (black) to 255 (white)
+type ImageOptions struct {
+	// Threshold is the brightest gray value of a wall, by default only black pixels are walls
+	Threshold uint8
+	// WeightScale maps the darkness of the other pixels to WeightedNodes,
+	// a pixel gets the weighting (255 - gray) * WeightScale / 255, 0 disables the weights
+	WeightScale int
+	// FlipY maps the top row of the image to the highest Y,
+	// so the grid looks like the image with its origin "bottom left"
+	FlipY bool
+}
+
+// ConfigFromImage creates a Config with the size of the image
+// and an invalid node for every wall pixel
+func ConfigFromImage(img image.Image, opts ImageOptions) (Config, error) {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width < 1 || height < 1 {
+		return Config{}, errors.New("image must be min 1x1 pixels")
+	}
+
+	config := Config{GridWidth: width, GridHeight: height}
+	for py := 0; py < height; py++ {
+		y := py
+		if opts.FlipY {
+			y = height - 1 - py
+		}
+		for px := 0; px < width; px++ {
+			gray := color.GrayModel.Convert(img.At(bounds.Min.X+px, bounds.Min.Y+py)).(color.Gray).Y
+			if gray <= opts.Threshold {
+				config.InvalidNodes = append(config.InvalidNodes, Node{X: px, Y: y})
+				continue
+			}
+			if opts.WeightScale > 0 {
+				if weighting := (255 - int(gray)) * opts.WeightScale / 255; weighting > 0 {
+					config.WeightedNodes = append(config.WeightedNodes, Node{X: px, Y: y, Weighting: weighting})
+				}
+			}
+		}
+	}
+	return config, nil
+}
@@ -0,0 +1,59 @@
+package astar
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestConfigFromImage(t *testing.T) {
+
+	// image rows from the top
+	// [W] [ ] [ ]   W: wall (black)
+	// [ ] [G] [ ]   G: gray 127
+	img := image.NewGray(image.Rect(10, 10, 13, 12))
+	for y := 10; y < 12; y++ {
+		for x := 10; x < 13; x++ {
+			img.SetGray(x, y, color.Gray{Y: 255})
+		}
+	}
+	img.SetGray(10, 10, color.Gray{Y: 0})
+	img.SetGray(11, 11, color.Gray{Y: 127})
+
+	config, err := ConfigFromImage(img, ImageOptions{})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if config.GridWidth != 3 || config.GridHeight != 2 {
+		t.Error("grid should have the image size: ", config.GridWidth, config.GridHeight)
+	}
+	if len(config.InvalidNodes) != 1 || config.InvalidNodes[0].X != 0 || config.InvalidNodes[0].Y != 0 {
+		t.Error("black pixel should be the only wall: ", config.InvalidNodes)
+	}
+	if len(config.WeightedNodes) != 0 {
+		t.Error("weights should be disabled: ", config.WeightedNodes)
+	}
+
+	// flipped, a higher threshold and weights
+	config, err = ConfigFromImage(img, ImageOptions{Threshold: 127, WeightScale: 10, FlipY: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	invalidList := NewNodeSet()
+	invalidList.Add(config.InvalidNodes...)
+	if invalidList.Len() != 2 || !invalidList.Contains(Node{X: 0, Y: 1}) || !invalidList.Contains(Node{X: 1, Y: 0}) {
+		t.Error("dark pixels should be walls of the flipped grid: ", config.InvalidNodes)
+	}
+
+	config, _ = ConfigFromImage(img, ImageOptions{WeightScale: 10, FlipY: true})
+	if len(config.WeightedNodes) != 1 || config.WeightedNodes[0].X != 1 || config.WeightedNodes[0].Y != 0 || config.WeightedNodes[0].Weighting != 5 {
+		t.Error("gray pixel should be weighted: ", config.WeightedNodes)
+	}
+	if _, err = New(config); err != nil {
+		t.Error("config should be valid", err)
+	}
+
+	if _, err = ConfigFromImage(image.NewGray(image.Rect(0, 0, 0, 5)), ImageOptions{}); err == nil {
+		t.Error("too small image should be invalid")
+	}
+}
@@ -0,0 +1,276 @@
+package astar
+
+import (
+	"container/heap"
+	"errors"
+)
+
+// IncrementalPlanner keeps its search state between queries
+// and repairs the path after cells changed instead of searching from scratch
+//
+// It implements D* Lite, the search runs backward from the goal so
+// obstacles added or removed near the moving start are cheap to update.
+// The planner uses the grid, the InvalidNodes, the costs and the heuristic of the Config
+type IncrementalPlanner struct {
+	finder    *PathFinder
+	startNode Node
+	goalNode  Node
+	lastStart Node
+	km        int // 起点移动后累计的key修正值
+	cells     map[[2]int]*plannerCell
+	queue     plannerQueue
+	changed   NodeSet // 自上次Plan后变化的格子
+}
+
+// plannerCell holds the D* Lite values of a cell
+type plannerCell struct {
+	x, y   int
+	g, rhs int
+	key    [2]int
+	index  int // 在queue中的位置, -1表示不在queue中
+}
+
+// NewIncrementalPlanner creates a new IncrementalPlanner instance
+func NewIncrementalPlanner(config Config) (*IncrementalPlanner, error) {
+	if config.Unbounded {
+		return nil, ErrUnboundedGrid
+	}
+	finder, err := New(config)
+	if err != nil {
+		return nil, err
+	}
+	return &IncrementalPlanner{finder: finder}, nil
+}
+
+// Init resets the planner for a new start and goal
+// cells changed by UpdateCell stay blocked or free
+func (p *IncrementalPlanner) Init(startNode, goalNode Node) {
+	p.startNode = startNode
+	p.goalNode = goalNode
+	p.lastStart = startNode
+	p.km = 0
+	p.cells = make(map[[2]int]*plannerCell)
+	p.queue = plannerQueue{}
+	p.changed.Clear()
+
+	goal := p.cell(goalNode.X, goalNode.Y)
+	goal.rhs = 0
+	p.push(goal)
+}
+
+// SetStart moves the start node, e.g. after the unit walked along the path
+// the search state is kept
+func (p *IncrementalPlanner) SetStart(startNode Node) {
+	p.startNode = startNode
+}
+
+// UpdateCell marks the cell as blocked or free
+// the path is repaired by the next call to Plan
+func (p *IncrementalPlanner) UpdateCell(x, y int, blocked bool) {
+	node := Node{X: x, Y: y}
+	if p.finder.invalidList.Contains(node) == blocked {
+		return
+	}
+	if blocked {
+		p.finder.AddObstacle(x, y)
+	} else {
+		p.finder.RemoveObstacle(x, y)
+	}
+	p.changed.Add(node)
+	// the clearance of the cells around changed as well
+	radius := p.finder.config.AgentRadius
+	for dy := -radius; dy <= radius; dy++ {
+		for dx := -radius; dx <= radius; dx++ {
+			if p.finder.inBounds(x+dx, y+dy) {
+				p.changed.Add(Node{X: x + dx, Y: y + dy})
+			}
+		}
+	}
+}
+
+// Plan returns the path from the start to the goal node
+// The return value has the same format as FindPath
+//
+// If no path was found it returns nil and an error
+func (p *IncrementalPlanner) Plan() ([]Node, error) {
+	if p.cells == nil {
+		return nil, errors.New("planner is not initialised")
+	}
+
+	// the keys in the queue got smaller by the distance the start moved
+	p.km += p.h(p.lastStart, p.startNode)
+	p.lastStart = p.startNode
+
+	if !p.changed.IsEmpty() {
+		for _, node := range p.changed.All() {
+			p.updateCell(p.cell(node.X, node.Y))
+			for _, neighbor := range p.finder.GetNeighborNodes(nil, node) {
+				p.updateCell(p.cell(neighbor.X, neighbor.Y))
+			}
+		}
+		p.changed.Clear()
+	}
+
+	p.computeShortestPath()
+
+	start := p.cell(p.startNode.X, p.startNode.Y)
+	if start.g >= infCost {
+		return nil, ErrorNoPath
+	}
+	return p.extractPath(), nil
+}
+
+// computeShortestPath expands cells until the start cell is consistent
+func (p *IncrementalPlanner) computeShortestPath() {
+	start := p.cell(p.startNode.X, p.startNode.Y)
+	for p.queue.Len() > 0 && (lessKey(p.queue[0].key, p.calculateKey(start)) || start.rhs != start.g) {
+		current := p.queue[0]
+		oldKey := current.key
+		newKey := p.calculateKey(current)
+
+		if lessKey(oldKey, newKey) {
+			current.key = newKey
+			heap.Fix(&p.queue, current.index)
+			continue
+		}
+
+		heap.Remove(&p.queue, current.index)
+		node := Node{X: current.x, Y: current.y}
+		if current.g > current.rhs {
+			current.g = current.rhs
+		} else {
+			current.g = infCost
+			p.updateCell(current)
+		}
+		for _, neighbor := range p.finder.GetNeighborNodes(nil, node) {
+			p.updateCell(p.cell(neighbor.X, neighbor.Y))
+		}
+	}
+}
+
+// updateCell recalculates rhs of the cell and fixes its queue state
+func (p *IncrementalPlanner) updateCell(c *plannerCell) {
+	if c.x != p.goalNode.X || c.y != p.goalNode.Y {
+		c.rhs = infCost
+		node := Node{X: c.x, Y: c.y}
+		if !p.finder.invalidList.Contains(node) {
+			for _, neighbor := range p.finder.GetNeighborNodes(nil, node) {
+				next := p.cell(neighbor.X, neighbor.Y)
+				if rhs := addCost(p.finder.stepCost(node, neighbor), next.g); rhs < c.rhs {
+					c.rhs = rhs
+				}
+			}
+		}
+	}
+
+	if c.index >= 0 {
+		heap.Remove(&p.queue, c.index)
+	}
+	if c.g != c.rhs {
+		p.push(c)
+	}
+}
+
+// extractPath follows the cheapest successors from the start to the goal
+func (p *IncrementalPlanner) extractPath() []Node {
+	current := Node{X: p.startNode.X, Y: p.startNode.Y}
+	path := []Node{current}
+	g := 0
+	// a path cannot visit more cells than the grid has
+	for i := 0; i < p.finder.config.GridWidth*p.finder.config.GridHeight; i++ {
+		if current.X == p.goalNode.X && current.Y == p.goalNode.Y {
+			break
+		}
+
+		var next Node
+		best := infCost
+		for _, neighbor := range p.finder.GetNeighborNodes(nil, current) {
+			c := addCost(p.finder.stepCost(current, neighbor), p.cell(neighbor.X, neighbor.Y).g)
+			if c < best {
+				best = c
+				next = Node{X: neighbor.X, Y: neighbor.Y}
+			}
+		}
+		if best >= infCost {
+			break
+		}
+
+		g += p.finder.stepCost(current, next)
+		next.g = g
+		next.f = g
+		path = append(path, next)
+		current = next
+	}
+	return path
+}
+
+// h returns the heuristic cost between two nodes
+func (p *IncrementalPlanner) h(nodeA, nodeB Node) int {
+	return p.finder.hCost(nodeA, nodeB)
+}
+
+// calculateKey returns the queue priority of the cell
+func (p *IncrementalPlanner) calculateKey(c *plannerCell) [2]int {
+	minG := c.g
+	if c.rhs < minG {
+		minG = c.rhs
+	}
+	return [2]int{addCost(addCost(minG, p.h(p.startNode, Node{X: c.x, Y: c.y})), p.km), minG}
+}
+
+// cell returns the state of the cell, unseen cells are created with infinite costs
+func (p *IncrementalPlanner) cell(x, y int) *plannerCell {
+	key := [2]int{x, y}
+	c, ok := p.cells[key]
+	if !ok {
+		c = &plannerCell{x: x, y: y, g: infCost, rhs: infCost, index: -1}
+		p.cells[key] = c
+	}
+	return c
+}
+
+// push adds the cell to the queue with its current key
+func (p *IncrementalPlanner) push(c *plannerCell) {
+	c.key = p.calculateKey(c)
+	heap.Push(&p.queue, c)
+}
+
+// lessKey compares two queue keys lexicographically
+func lessKey(a, b [2]int) bool {
+	if a[0] != b[0] {
+		return a[0] < b[0]
+	}
+	return a[1] < b[1]
+}
+
+// plannerQueue implements heap.Interface ordered by the cell keys
+type plannerQueue []*plannerCell
+
+func (q plannerQueue) Len() int {
+	return len(q)
+}
+
+func (q plannerQueue) Less(i, j int) bool {
+	return lessKey(q[i].key, q[j].key)
+}
+
+func (q plannerQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *plannerQueue) Push(x interface{}) {
+	c := x.(*plannerCell)
+	c.index = len(*q)
+	*q = append(*q, c)
+}
+
+func (q *plannerQueue) Pop() interface{} {
+	old := *q
+	last := len(old) - 1
+	c := old[last]
+	c.index = -1
+	*q = old[:last]
+	return c
+}
@@ -0,0 +1,124 @@
+package astar
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestIncrementalPlanner_Plan(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [ ]   S: StartNode
+	// [ ] [ ] [ ] [ ] [ ]   E: EndNode
+	// [S] [ ] [X] [ ] [E]   X: cell blocked after the first plan
+	// [ ] [ ] [ ] [ ] [ ]
+	// [ ] [ ] [ ] [ ] [ ]
+
+	startNode := Node{X: 0, Y: 2}
+	endNode := Node{X: 4, Y: 2}
+
+	p, err := NewIncrementalPlanner(Config{GridWidth: 5, GridHeight: 5})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+
+	if _, err = p.Plan(); err == nil {
+		t.Error("plan without Init should fail")
+	}
+
+	p.Init(startNode, endNode)
+	foundPath, err := p.Plan()
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if len(foundPath) != 5 || foundPath[len(foundPath)-1].X != endNode.X || foundPath[len(foundPath)-1].Y != endNode.Y {
+		t.Error("path should go straight to the end node: ", foundPath)
+	}
+
+	// block the straight way
+	p.UpdateCell(2, 2, true)
+	foundPath, err = p.Plan()
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if len(foundPath) != 7 || foundPath[len(foundPath)-1].g != 6 {
+		t.Error("path should walk around the blocked cell: ", foundPath)
+	}
+	pathList := NewList()
+	pathList.Add(foundPath...)
+	if pathList.Contains(Node{X: 2, Y: 2}) {
+		t.Error("path should not contain the blocked cell: ", foundPath)
+	}
+
+	// close the whole column
+	for y := 0; y < 5; y++ {
+		p.UpdateCell(2, y, true)
+	}
+	if _, err = p.Plan(); err != ErrorNoPath {
+		t.Error("there should be no path", err)
+	}
+
+	// open it again
+	for y := 0; y < 5; y++ {
+		p.UpdateCell(2, y, false)
+	}
+	foundPath, err = p.Plan()
+	if err != nil || len(foundPath) != 5 {
+		t.Error("path should go straight again: ", foundPath, err)
+	}
+
+	// move the start along the path
+	p.SetStart(Node{X: 2, Y: 2})
+	foundPath, err = p.Plan()
+	if err != nil || len(foundPath) != 3 || foundPath[0].X != 2 {
+		t.Error("path should start at the new start node: ", foundPath, err)
+	}
+}
+
+func TestIncrementalPlanner_MatchesFindPath(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 14, Y: 14}
+
+	for _, allowDiagonal := range []bool{false, true} {
+		config := Config{GridWidth: 15, GridHeight: 15, AllowDiagonal: allowDiagonal, Heuristic: ChebyshevDistance}
+		p, err := NewIncrementalPlanner(config)
+		if err != nil {
+			t.Fatal("there should be no error", err)
+		}
+		p.Init(startNode, endNode)
+
+		blocked := make(map[[2]int]bool)
+		for round := 0; round < 30; round++ {
+			// toggle a few random cells and compare with a fresh search
+			for i := 0; i < 8; i++ {
+				x, y := r.Intn(15), r.Intn(15)
+				if (x == startNode.X && y == startNode.Y) || (x == endNode.X && y == endNode.Y) {
+					continue
+				}
+				blocked[[2]int{x, y}] = !blocked[[2]int{x, y}]
+				p.UpdateCell(x, y, blocked[[2]int{x, y}])
+			}
+
+			config.InvalidNodes = nil
+			for cell, isBlocked := range blocked {
+				if isBlocked {
+					config.InvalidNodes = append(config.InvalidNodes, Node{X: cell[0], Y: cell[1]})
+				}
+			}
+			config.DisableHeuristic = true
+			a, err := New(config)
+			if err != nil {
+				t.Fatal("there should be no error", err)
+			}
+			_, wantCost, wantErr := a.FindPathWithCost(nil, startNode, endNode)
+
+			foundPath, err := p.Plan()
+			if (err == nil) != (wantErr == nil) {
+				t.Fatal("planner and FindPath disagree", err, wantErr)
+			}
+			if err == nil && foundPath[len(foundPath)-1].g != wantCost {
+				t.Error("planner cost should be optimal", foundPath[len(foundPath)-1].g, wantCost)
+			}
+		}
+	}
+}
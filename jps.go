@@ -0,0 +1,300 @@
+package astar
+
+// FindPathJPS starts the jump point search for the given start and end node
+// The return value has the same format as FindPath, every cell of the path is included
+//
+// Jump point search skips the symmetric paths of uniform-cost 8-directional grids
+// and only opens the jump points where the direction may change,
+// so it expands far fewer nodes than FindPath on open grids.
+// If the grid is not uniform-cost (AllowDiagonal is not set, WeightedNodes, WeightedRegions, CostFunc, MoveCost or Neighbors are used)
+// a diagonal step costs less than one or more than two orthogonal steps
+// or AllowBlockedEndpoints, BlockedEdges, WrapEdges, TurnPenalty or CellWidth and CellHeight are set
+// or GoalRadius without an IContext or ctx is an ITimedContext, the DiagonalPolicy is DiagonalNoCornerCutting or the grid is Unbounded,
+// the rays could run forever there, it falls back to FindPath.
+// ctx.IsNearEnough is not used, the search ends at the exact end node
+func (a *PathFinder) FindPathJPS(ctx IContext, startNode, endNode Node) ([]Node, error) {
+	if !a.config.AllowDiagonal || len(a.config.WeightedNodes) > 0 || len(a.config.WeightedRegions) > 0 || a.config.CostFunc != nil || a.config.MoveCost != nil || a.config.Neighbors != nil ||
+		a.config.AllowBlockedEndpoints || len(a.config.BlockedEdges) > 0 || a.config.WrapEdges || a.config.TurnPenalty > 0 ||
+		a.cellCosts != nil || isTimed(ctx) || a.config.DiagonalPolicy == DiagonalNoCornerCutting || a.config.Unbounded || a.usesGoalRadius(ctx) {
+		return a.FindPath(ctx, startNode, endNode)
+	}
+	// the pruning expects diagonal first paths to be optimal
+	if a.config.DiagonalCost < a.config.OrthogonalCost || a.config.DiagonalCost > 2*a.config.OrthogonalCost {
+		return a.FindPath(ctx, startNode, endNode)
+	}
+
+	a.obstacleMu.RLock()
+	defer a.obstacleMu.RUnlock()
+	endNode = a.snapEnd(ctx, endNode)
+
+	j := &jumpSearch{finder: a, ctx: ctx, endNode: endNode}
+	if err := a.checkEndpoints(ctx, startNode, endNode, false); err != nil {
+		return nil, err
+	}
+
+	var openList Heap
+	var closedList NodeSet
+	startNode.parent = nil
+	openList.Add(startNode)
+
+	for !openList.IsEmpty() {
+		currentNode, _ := openList.GetMinFNode()
+		openList.Remove(currentNode)
+		closedList.Add(currentNode)
+
+		if currentNode.X == endNode.X && currentNode.Y == endNode.Y {
+			return a.getNodePath(j.expandPath(currentNode)), nil
+		}
+
+		for _, neighbor := range j.prunedNeighbors(currentNode) {
+			jumpX, jumpY, ok := j.jump(neighbor[0], neighbor[1], currentNode.X, currentNode.Y)
+			if !ok {
+				continue
+			}
+			jumpNode := Node{X: jumpX, Y: jumpY, parent: &currentNode}
+			if closedList.Contains(jumpNode) {
+				continue
+			}
+
+			jumpNode.g = currentNode.g + j.distance(currentNode, jumpNode)
+			jumpNode.h = a.H(jumpNode, endNode) * a.straightCost()
+			jumpNode.f = jumpNode.g + jumpNode.h
+
+			openNode, ok := openList.Get(jumpNode)
+			if !ok {
+				openList.Add(jumpNode)
+			} else if jumpNode.g < openNode.g {
+				openList.Update(jumpNode)
+			}
+		}
+	}
+
+	return nil, ErrorNoPath
+}
+
+// jumpSearch holds the state of a single jump point search
+type jumpSearch struct {
+	finder  *PathFinder
+	ctx     IContext
+	endNode Node
+}
+
+// walkable checks if the cell is inside the grid and not blocked
+func (j *jumpSearch) walkable(x, y int) bool {
+	return j.finder.isWalkable(j.ctx, x, y)
+}
+
+// isEnd checks if the cell is the end node
+func (j *jumpSearch) isEnd(x, y int) bool {
+	return x == j.endNode.X && y == j.endNode.Y
+}
+
+// jump moves from x, y away from the parent px, py until it finds a jump point
+// it returns false if the ray hits an obstacle or the grid border
+func (j *jumpSearch) jump(x, y, px, py int) (int, int, bool) {
+	dx, dy := x-px, y-py
+	for {
+		if !j.walkable(x, y) {
+			return 0, 0, false
+		}
+		if j.isEnd(x, y) || j.hasForcedNeighbor(x, y, dx, dy) {
+			return x, y, true
+		}
+
+		if dx != 0 && dy != 0 {
+			// a diagonal move is a jump point if one of its straight rays finds one
+			if _, _, ok := j.jump(x+dx, y, x, y); ok {
+				return x, y, true
+			}
+			if _, _, ok := j.jump(x, y+dy, x, y); ok {
+				return x, y, true
+			}
+			if j.finder.config.DisallowCornerCutting && !(j.walkable(x+dx, y) && j.walkable(x, y+dy)) {
+				return 0, 0, false
+			}
+		}
+
+		x, y = x+dx, y+dy
+	}
+}
+
+// hasForcedNeighbor checks if a neighbor of x, y can only be reached optimally
+// through x, y when moving in direction dx, dy
+func (j *jumpSearch) hasForcedNeighbor(x, y, dx, dy int) bool {
+	if j.finder.config.DisallowCornerCutting {
+		switch {
+		case dx != 0 && dy != 0:
+			return false
+		case dx != 0:
+			return (j.walkable(x, y-1) && !j.walkable(x-dx, y-1)) ||
+				(j.walkable(x, y+1) && !j.walkable(x-dx, y+1))
+		default:
+			return (j.walkable(x-1, y) && !j.walkable(x-1, y-dy)) ||
+				(j.walkable(x+1, y) && !j.walkable(x+1, y-dy))
+		}
+	}
+
+	switch {
+	case dx != 0 && dy != 0:
+		return (j.walkable(x-dx, y+dy) && !j.walkable(x-dx, y)) ||
+			(j.walkable(x+dx, y-dy) && !j.walkable(x, y-dy))
+	case dx != 0:
+		return (j.walkable(x+dx, y+1) && !j.walkable(x, y+1)) ||
+			(j.walkable(x+dx, y-1) && !j.walkable(x, y-1))
+	default:
+		return (j.walkable(x+1, y+dy) && !j.walkable(x+1, y)) ||
+			(j.walkable(x-1, y+dy) && !j.walkable(x-1, y))
+	}
+}
+
+// prunedNeighbors returns the cells to jump to from the node
+// only the natural and forced neighbors in the direction of the parent are kept
+func (j *jumpSearch) prunedNeighbors(node Node) [][2]int {
+	var neighbors [][2]int
+	x, y := node.X, node.Y
+
+	if node.parent == nil {
+		for _, neighbor := range j.finder.appendNeighborNodes(j.ctx, nil, &node, nil) {
+			neighbors = append(neighbors, [2]int{neighbor.X, neighbor.Y})
+		}
+		return neighbors
+	}
+
+	dx, dy := sign(x-node.parent.X), sign(y-node.parent.Y)
+	add := func(nx, ny int) {
+		neighbors = append(neighbors, [2]int{nx, ny})
+	}
+
+	if j.finder.config.DisallowCornerCutting {
+		switch {
+		case dx != 0 && dy != 0:
+			walkX, walkY := j.walkable(x+dx, y), j.walkable(x, y+dy)
+			if walkY {
+				add(x, y+dy)
+			}
+			if walkX {
+				add(x+dx, y)
+			}
+			if walkX && walkY {
+				add(x+dx, y+dy)
+			}
+		case dx != 0:
+			walkNext, walkUp, walkDown := j.walkable(x+dx, y), j.walkable(x, y+1), j.walkable(x, y-1)
+			if walkNext {
+				add(x+dx, y)
+				if walkUp {
+					add(x+dx, y+1)
+				}
+				if walkDown {
+					add(x+dx, y-1)
+				}
+			}
+			if walkUp {
+				add(x, y+1)
+			}
+			if walkDown {
+				add(x, y-1)
+			}
+		default:
+			walkNext, walkRight, walkLeft := j.walkable(x, y+dy), j.walkable(x+1, y), j.walkable(x-1, y)
+			if walkNext {
+				add(x, y+dy)
+				if walkRight {
+					add(x+1, y+dy)
+				}
+				if walkLeft {
+					add(x-1, y+dy)
+				}
+			}
+			if walkRight {
+				add(x+1, y)
+			}
+			if walkLeft {
+				add(x-1, y)
+			}
+		}
+		return neighbors
+	}
+
+	switch {
+	case dx != 0 && dy != 0:
+		add(x, y+dy)
+		add(x+dx, y)
+		add(x+dx, y+dy)
+		if !j.walkable(x-dx, y) {
+			add(x-dx, y+dy)
+		}
+		if !j.walkable(x, y-dy) {
+			add(x+dx, y-dy)
+		}
+	case dx != 0:
+		add(x+dx, y)
+		if !j.walkable(x, y+1) {
+			add(x+dx, y+1)
+		}
+		if !j.walkable(x, y-1) {
+			add(x+dx, y-1)
+		}
+	default:
+		add(x, y+dy)
+		if !j.walkable(x+1, y) {
+			add(x+1, y+dy)
+		}
+		if !j.walkable(x-1, y) {
+			add(x-1, y+dy)
+		}
+	}
+	return neighbors
+}
+
+// distance returns the cost between two jump points on a straight or diagonal line
+func (j *jumpSearch) distance(from, to Node) int {
+	dx, dy := absInt(to.X-from.X), absInt(to.Y-from.Y)
+	if dx == 0 || dy == 0 {
+		return (dx + dy) * j.finder.config.OrthogonalCost
+	}
+	return dx * j.finder.config.DiagonalCost
+}
+
+// expandPath fills the cells between the jump points of the chain
+// so the path has the same format as the one of FindPath
+func (j *jumpSearch) expandPath(endNode Node) Node {
+	var jumpPoints []Node
+	for node := &endNode; node != nil; node = node.parent {
+		jumpPoints = append(jumpPoints, *node)
+	}
+
+	currentNode := jumpPoints[len(jumpPoints)-1]
+	currentNode.parent = nil
+	for i := len(jumpPoints) - 2; i >= 0; i-- {
+		target := jumpPoints[i]
+		dx, dy := sign(target.X-currentNode.X), sign(target.Y-currentNode.Y)
+		for currentNode.X != target.X || currentNode.Y != target.Y {
+			parentNode := currentNode
+			currentNode = Node{X: parentNode.X + dx, Y: parentNode.Y + dy, parent: &parentNode}
+			currentNode.g = parentNode.g + j.finder.moveCost(parentNode, currentNode)
+			currentNode.h = j.finder.H(currentNode, j.endNode) * j.finder.straightCost()
+			currentNode.f = currentNode.g + currentNode.h
+		}
+	}
+	return currentNode
+}
+
+// sign returns -1, 0 or 1 for the sign of v
+func sign(v int) int {
+	switch {
+	case v < 0:
+		return -1
+	case v > 0:
+		return 1
+	}
+	return 0
+}
+
+// absInt returns the absolute value of v
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,139 @@
+package astar
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestAstar_FindPathJPS(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [ ] [ ] [E]   S: StartNode
+	// [ ] [ ] [ ] [ ] [ ] [ ] [ ]   E: EndNode
+	// [ ] [ ] [O] [O] [O] [O] [ ]   O: ObstacleNode
+	// [ ] [ ] [ ] [ ] [ ] [O] [ ]
+	// [ ] [ ] [ ] [ ] [ ] [O] [ ]
+	// [ ] [ ] [ ] [ ] [ ] [O] [ ]
+	// [S] [ ] [ ] [ ] [ ] [ ] [ ]
+
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 6, Y: 6}
+	obstacleNodes := []Node{
+		{X: 2, Y: 4},
+		{X: 3, Y: 4},
+		{X: 4, Y: 4},
+		{X: 5, Y: 4},
+		{X: 5, Y: 3},
+		{X: 5, Y: 2},
+		{X: 5, Y: 1},
+	}
+
+	config := Config{GridWidth: 7, GridHeight: 7, InvalidNodes: obstacleNodes, AllowDiagonal: true, Heuristic: ChebyshevDistance}
+	a, err := New(config)
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	_, wantCost, err := a.FindPathWithCost(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+
+	foundPath, err := a.FindPathJPS(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if foundPath[len(foundPath)-1].X != endNode.X || foundPath[len(foundPath)-1].Y != endNode.Y || foundPath[len(foundPath)-1].g != wantCost {
+		t.Error("path should end at the end node with the optimal cost: ", wantCost, foundPath)
+	}
+	checkPathConnected(t, a, startNode, foundPath)
+
+	// blocked end node
+	if _, err = a.FindPathJPS(nil, startNode, Node{X: 5, Y: 2}); err != ErrEndBlocked {
+		t.Error("end node should be blocked", err)
+	}
+
+	// weighted grids fall back to FindPath
+	config.WeightedNodes = []Node{{X: 3, Y: 3, Weighting: 50}}
+	a, err = New(config)
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	_, wantCost, _ = a.FindPathWithCost(nil, startNode, endNode)
+	foundPath, err = a.FindPathJPS(nil, startNode, endNode)
+	if err != nil || foundPath[len(foundPath)-1].g != wantCost {
+		t.Error("weighted grid should use FindPath", err, wantCost, foundPath)
+	}
+}
+
+func TestAstar_FindPathJPSOptimal(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 24, Y: 24}
+
+	for i := 0; i < 100; i++ {
+		config := Config{
+			GridWidth:             25,
+			GridHeight:            25,
+			AllowDiagonal:         true,
+			DisallowCornerCutting: i%2 == 1,
+			Heuristic:             ChebyshevDistance,
+		}
+		for k := 0; k < 150; k++ {
+			node := Node{X: r.Intn(25), Y: r.Intn(25)}
+			if (node.X == startNode.X && node.Y == startNode.Y) || (node.X == endNode.X && node.Y == endNode.Y) {
+				continue
+			}
+			config.InvalidNodes = append(config.InvalidNodes, node)
+		}
+
+		a, err := New(config)
+		if err != nil {
+			t.Fatal("there should be no error", err)
+		}
+		_, wantCost, wantErr := a.FindPathWithCost(nil, startNode, endNode)
+		foundPath, err := a.FindPathJPS(nil, startNode, endNode)
+		if (err == nil) != (wantErr == nil) {
+			t.Fatal("jps and FindPath disagree", i, err, wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if foundPath[len(foundPath)-1].g != wantCost {
+			t.Error("jps cost should be optimal", i, foundPath[len(foundPath)-1].g, wantCost)
+		}
+		checkPathConnected(t, a, startNode, foundPath)
+	}
+}
+
+// checkPathConnected checks that the path begins at the start node
+// and every step is a valid neighbor of the step before
+func checkPathConnected(t *testing.T, a *PathFinder, startNode Node, path []Node) {
+	t.Helper()
+	if path[0].X != startNode.X || path[0].Y != startNode.Y {
+		t.Fatal("path should begin at the start node: ", path)
+	}
+	previous := startNode
+	for i := 1; i < len(path); i++ {
+		neighborList := NewList()
+		neighborList.Add(a.GetNeighborNodes(nil, previous)...)
+		if !neighborList.Contains(path[i]) {
+			t.Fatal("path is not connected at: ", path[i], path)
+		}
+		previous = path[i]
+	}
+}
+
+func TestAstar_FindPathJPSUnbounded(t *testing.T) {
+	// without an IBoundsContext every cell is in bounds, a jump ray never ends
+	a, err := New(Config{Unbounded: true, AllowDiagonal: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	startNode, endNode := Node{X: 0, Y: 0}, Node{X: 5, Y: 2}
+	foundPath, err := a.FindPathJPS(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if len(foundPath) != 6 || foundPath[5].X != endNode.X || foundPath[5].Y != endNode.Y {
+		t.Error("the path should be the one of FindPath: ", foundPath)
+	}
+}
@@ -0,0 +1,134 @@
+package astar
+
+import (
+	"context"
+	"errors"
+)
+
+// FindKShortestPaths returns up to k loopless paths from the start to the end node
+// in increasing cost order, the first one is the path of FindPath.
+// If fewer than k paths exist, all of them are returned
+//
+// It implements Yen's algorithm on top of FindPath: for every node of the last found path
+// a spur search runs from that node to the end node, the nodes of the path before it are blocked
+// and the steps the earlier paths took from it as well. So k paths cost up to
+// k times the path length FindPath calls, keep k and the grid small for every-frame use.
+// The obstacles are locked once for all searches.
+// The costs of the nodes are accumulated from the start node like in FindPath,
+// a turn at the node where a path leaves the earlier one costs TurnPenalty as well
+func (a *PathFinder) FindKShortestPaths(ctx IContext, startNode, endNode Node, k int) ([][]Node, error) {
+	if k < 1 {
+		return nil, errors.New("k must be min 1")
+	}
+
+	a.obstacleMu.RLock()
+	defer a.obstacleMu.RUnlock()
+
+	firstPath, err := a.findPathLocked(context.Background(), ctx, startNode, endNode, searchOptions{maxSteps: StepsNoLimit})
+	if err != nil {
+		return nil, err
+	}
+
+	paths := [][]Node{firstPath}
+	var candidates [][]Node // 候选路径, 按代价排序
+	for len(paths) < k {
+		lastPath := paths[len(paths)-1]
+		for i := 0; i < len(lastPath)-1; i++ {
+			spurNode := lastPath[i]
+			rootPath := lastPath[:i+1]
+
+			// block the root path and the steps of the known paths with the same root
+			rootNodes := make(map[[2]int]struct{}, i)
+			for _, node := range rootPath[:i] {
+				rootNodes[nodeKey(node)] = struct{}{}
+			}
+			usedSteps := make(map[[2]int]struct{})
+			for _, path := range paths {
+				if len(path) > i+1 && samePathNodes(path[:i+1], rootPath) {
+					usedSteps[nodeKey(path[i+1])] = struct{}{}
+				}
+			}
+			opts := searchOptions{
+				maxSteps: StepsNoLimit,
+				// the spur search continues the root path, a turn at the spur node costs TurnPenalty
+				headed: i > 0,
+				skipStep: func(from, to Node) bool {
+					if _, ok := rootNodes[nodeKey(to)]; ok {
+						return true
+					}
+					_, ok := usedSteps[nodeKey(to)]
+					return ok && from.X == spurNode.X && from.Y == spurNode.Y
+				},
+			}
+
+			if i > 0 {
+				opts.heading = a.stepDelta(lastPath[i-1], spurNode)
+			}
+
+			spurPath, err := a.findPathLocked(context.Background(), ctx, Node{X: spurNode.X, Y: spurNode.Y}, endNode, opts)
+			if err != nil {
+				continue
+			}
+
+			// the spur costs start at 0, continue them from the root
+			path := make([]Node, 0, len(rootPath)+len(spurPath)-1)
+			path = append(path, rootPath...)
+			for _, node := range spurPath[1:] {
+				node.g = addCost(node.g, spurNode.g)
+				node.f = addCost(node.f, spurNode.g)
+				path = append(path, node)
+			}
+			candidates = insertCandidate(candidates, paths, path)
+		}
+
+		if len(candidates) == 0 {
+			break
+		}
+		paths = append(paths, candidates[0])
+		candidates = candidates[1:]
+	}
+	return paths, nil
+}
+
+// insertCandidate adds the path to the candidates sorted by cost,
+// paths which are already known or candidates are dropped
+func insertCandidate(candidates, paths [][]Node, path []Node) [][]Node {
+	for _, known := range paths {
+		if samePathNodes(known, path) {
+			return candidates
+		}
+	}
+	cost := pathCost(path)
+	index := len(candidates)
+	for i, candidate := range candidates {
+		if samePathNodes(candidate, path) {
+			return candidates
+		}
+		// equal costs keep the order they were found in
+		if index == len(candidates) && pathCost(candidate) > cost {
+			index = i
+		}
+	}
+	candidates = append(candidates, nil)
+	copy(candidates[index+1:], candidates[index:])
+	candidates[index] = path
+	return candidates
+}
+
+// pathCost returns the accumulated cost of the last node of the path
+func pathCost(path []Node) int {
+	return path[len(path)-1].g
+}
+
+// samePathNodes checks if both paths visit the same cells in the same order
+func samePathNodes(pathA, pathB []Node) bool {
+	if len(pathA) != len(pathB) {
+		return false
+	}
+	for i := range pathA {
+		if pathA[i].X != pathB[i].X || pathA[i].Y != pathB[i].Y {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,105 @@
+package astar
+
+import "testing"
+
+func TestAstar_FindKShortestPaths(t *testing.T) {
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 2, Y: 2}
+
+	a, err := New(Config{GridWidth: 3, GridHeight: 3})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	wantPath, err := a.FindPath(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+
+	// 6 shortest paths with 4 steps, then the longer ones
+	paths, err := a.FindKShortestPaths(nil, startNode, endNode, 8)
+	if err != nil {
+		t.Fatal("there should be paths", err)
+	}
+	if len(paths) != 8 || !samePathNodes(paths[0], wantPath) {
+		t.Fatal("first path should be the one of FindPath: ", paths)
+	}
+	for i, path := range paths {
+		wantCost := 4
+		if i >= 6 {
+			wantCost = 6
+		}
+		if pathCost(path) != wantCost {
+			t.Error("paths should be in increasing cost order: ", i, pathCost(path))
+		}
+		if path[0] != startNode || path[len(path)-1].X != endNode.X || path[len(path)-1].Y != endNode.Y {
+			t.Error("path should run from start to end: ", path)
+		}
+
+		visited := NewNodeSet()
+		for j, node := range path {
+			if visited.Contains(node) {
+				t.Error("path should not visit a cell twice: ", path)
+			}
+			visited.Add(node)
+			if j > 0 && (ManhattanDistance(path[j-1], node) != 1 || node.g != j) {
+				t.Error("path should be connected with accumulated costs: ", path)
+			}
+		}
+		for _, other := range paths[:i] {
+			if samePathNodes(path, other) {
+				t.Error("paths should be distinct: ", path)
+			}
+		}
+	}
+
+	// [ ] [ ] [E]   S: StartNode
+	// [ ] [O] [ ]   E: EndNode
+	// [S] [ ] [ ]   O: ObstacleNode
+
+	// only the two ways around the obstacle exist
+	a.AddObstacle(1, 1)
+	if paths, err = a.FindKShortestPaths(nil, startNode, endNode, 5); err != nil || len(paths) != 2 {
+		t.Error("there should be 2 paths: ", paths, err)
+	}
+
+	a.AddObstacle(1, 0)
+	a.AddObstacle(0, 1)
+	if _, err = a.FindKShortestPaths(nil, startNode, endNode, 3); err != ErrorNoPath {
+		t.Error("there should be no path", err)
+	}
+	if _, err = a.FindKShortestPaths(nil, startNode, endNode, 0); err == nil {
+		t.Error("k 0 should be invalid")
+	}
+}
+
+func TestAstar_FindKShortestPathsTurnPenalty(t *testing.T) {
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 2, Y: 2}
+
+	a, err := New(Config{GridWidth: 3, GridHeight: 3, TurnPenalty: 5})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	paths, err := a.FindKShortestPaths(nil, startNode, endNode, 8)
+	if err != nil {
+		t.Fatal("there should be paths", err)
+	}
+	if len(paths) != 8 {
+		t.Fatal("there should be 8 paths: ", len(paths))
+	}
+	for i, path := range paths {
+		if i > 0 && pathCost(path) < pathCost(paths[i-1]) {
+			t.Error("the costs should never decrease: ", i, pathCost(path), pathCost(paths[i-1]))
+		}
+		// every step costs 1 and every turn 5
+		wantCost := len(path) - 1
+		for j := 2; j < len(path); j++ {
+			if a.stepDelta(path[j-2], path[j-1]) != a.stepDelta(path[j-1], path[j]) {
+				wantCost += 5
+			}
+		}
+		if pathCost(path) != wantCost {
+			t.Error("the cost should include the turn at the spur node: ", path, pathCost(path), wantCost)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package astar
+
+import "errors"
+
+// Node3D is a cell of a layered grid
+// Z is the floor, X and Y the coordinates on the floor starting "bottom left"
+type Node3D struct {
+	X, Y, Z int
+}
+
+// Config3D holds the settings of a layered grid
+//
+// GridWidth and GridHeight are the size of every floor, GridDepth the number of floors
+// InvalidNodes can be used to add not accessible cells like walls
+// Connectors are the cells with stairs, a connector at X/Y/Z links it
+// with the cell X/Y/Z+1 of the floor above in both directions
+type Config3D struct {
+	GridWidth, GridHeight, GridDepth int
+	InvalidNodes                     []Node3D
+	Connectors                       []Node3D
+}
+
+// PathFinder3D searches paths on a grid with several floors
+// it moves 4-directional on a floor and changes the floor only at the connectors
+//
+// The floors are stacked into one plane internally, so the search
+// reuses the open list, the costs and the concurrency guarantee of PathFinder
+type PathFinder3D struct {
+	finder     *PathFinder
+	config     Config3D
+	connectors map[Node3D]bool
+}
+
+// NewPathFinder3D creates a new PathFinder3D instance
+func NewPathFinder3D(config Config3D) (*PathFinder3D, error) {
+	if config.GridDepth < 1 {
+		return nil, errors.New("GridDepth must be min 1")
+	}
+
+	p := &PathFinder3D{config: config, connectors: make(map[Node3D]bool, len(config.Connectors))}
+	for _, connector := range config.Connectors {
+		p.connectors[connector] = true
+	}
+
+	invalidNodes := make([]Node, 0, len(config.InvalidNodes))
+	for _, node := range config.InvalidNodes {
+		invalidNodes = append(invalidNodes, p.flatten(node))
+	}
+
+	finder, err := New(Config{
+		GridWidth:    config.GridWidth,
+		GridHeight:   config.GridHeight * config.GridDepth,
+		InvalidNodes: invalidNodes,
+		Heuristic: func(nodeA, nodeB Node) int {
+			return ManhattanDistance3D(p.unflatten(nodeA), p.unflatten(nodeB))
+		},
+		Neighbors: func(ctx IContext, node Node) []Node {
+			return p.neighbors(p.unflatten(node))
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	p.finder = finder
+	return p, nil
+}
+
+// FindPath starts the a* algorithm for the given start and end node
+// The path is ordered from start to goal and includes both nodes
+//
+// If no path was found it returns nil and an error
+func (p *PathFinder3D) FindPath(startNode, endNode Node3D) ([]Node3D, error) {
+	if !p.inBounds(startNode) || !p.inBounds(endNode) {
+		return nil, ErrOutOfBounds
+	}
+
+	foundPath, err := p.finder.FindPath(nil, p.flatten(startNode), p.flatten(endNode))
+	if err != nil {
+		return nil, err
+	}
+
+	nodePath := make([]Node3D, 0, len(foundPath))
+	for _, node := range foundPath {
+		nodePath = append(nodePath, p.unflatten(node))
+	}
+	return nodePath, nil
+}
+
+// ManhattanDistance3D returns the sum of the absolute X, Y and Z distances
+func ManhattanDistance3D(nodeA, nodeB Node3D) int {
+	return absInt(nodeA.X-nodeB.X) + absInt(nodeA.Y-nodeB.Y) + absInt(nodeA.Z-nodeB.Z)
+}
+
+// neighbors returns the cells on the same floor and the ones behind a connector
+// blocked cells are dropped by the PathFinder
+func (p *PathFinder3D) neighbors(node Node3D) []Node {
+	candidates := []Node3D{
+		{X: node.X, Y: node.Y + 1, Z: node.Z},
+		{X: node.X, Y: node.Y - 1, Z: node.Z},
+		{X: node.X - 1, Y: node.Y, Z: node.Z},
+		{X: node.X + 1, Y: node.Y, Z: node.Z},
+	}
+	if p.connectors[node] {
+		candidates = append(candidates, Node3D{X: node.X, Y: node.Y, Z: node.Z + 1})
+	}
+	if below := (Node3D{X: node.X, Y: node.Y, Z: node.Z - 1}); p.connectors[below] {
+		candidates = append(candidates, below)
+	}
+
+	var neighborNodes []Node
+	for _, candidate := range candidates {
+		// a step over the floor border would land on the next floor of the plane
+		if p.inBounds(candidate) {
+			neighborNodes = append(neighborNodes, p.flatten(candidate))
+		}
+	}
+	return neighborNodes
+}
+
+// inBounds checks if the cell is on one of the floors
+func (p *PathFinder3D) inBounds(node Node3D) bool {
+	return node.X >= 0 && node.X < p.config.GridWidth &&
+		node.Y >= 0 && node.Y < p.config.GridHeight &&
+		node.Z >= 0 && node.Z < p.config.GridDepth
+}
+
+// flatten maps the cell to the plane, the floors are stacked along Y
+func (p *PathFinder3D) flatten(node Node3D) Node {
+	return Node{X: node.X, Y: node.Z*p.config.GridHeight + node.Y}
+}
+
+// unflatten maps a node of the plane back to its floor
+func (p *PathFinder3D) unflatten(node Node) Node3D {
+	return Node3D{X: node.X, Y: node.Y % p.config.GridHeight, Z: node.Y / p.config.GridHeight}
+}
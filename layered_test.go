@@ -0,0 +1,72 @@
+package astar
+
+import "testing"
+
+func TestPathFinder3D_FindPath(t *testing.T) {
+
+	// floor 0                floor 1
+	// [ ] [ ] [O] [ ] [E]    [ ] [ ] [ ] [ ] [ ]   S: StartNode
+	// [ ] [ ] [O] [ ] [ ]    [ ] [ ] [ ] [ ] [ ]   E: EndNode
+	// [ ] [ ] [O] [ ] [ ]    [ ] [ ] [ ] [ ] [ ]   O: ObstacleNode
+	// [ ] [ ] [O] [ ] [ ]    [ ] [ ] [ ] [ ] [ ]   C: Connector
+	// [S] [C] [O] [C] [ ]    [ ] [ ] [ ] [ ] [ ]
+
+	startNode := Node3D{X: 0, Y: 0, Z: 0}
+	endNode := Node3D{X: 4, Y: 4, Z: 0}
+	var obstacleNodes []Node3D
+	for y := 0; y < 5; y++ {
+		obstacleNodes = append(obstacleNodes, Node3D{X: 2, Y: y, Z: 0})
+	}
+
+	config := Config3D{GridWidth: 5, GridHeight: 5, GridDepth: 2, InvalidNodes: obstacleNodes}
+	p, err := NewPathFinder3D(config)
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+
+	// without stairs the wall splits the floor
+	if _, err = p.FindPath(startNode, endNode); err != ErrorNoPath {
+		t.Error("there should be no path", err)
+	}
+
+	config.Connectors = []Node3D{{X: 1, Y: 0, Z: 0}, {X: 3, Y: 0, Z: 0}}
+	p, err = NewPathFinder3D(config)
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, err := p.FindPath(startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path over the upper floor", err)
+	}
+
+	// 1 step to the stairs, up, 2 steps, down, 5 steps
+	if len(foundPath) != 11 {
+		t.Error("path should have 11 nodes: ", foundPath)
+	}
+	if foundPath[0] != startNode || foundPath[len(foundPath)-1] != endNode {
+		t.Error("path should run from start to end: ", foundPath)
+	}
+	for i := 1; i < len(foundPath); i++ {
+		if ManhattanDistance3D(foundPath[i-1], foundPath[i]) != 1 {
+			t.Error("path should move one cell per step: ", foundPath[i-1], foundPath[i])
+		}
+		if foundPath[i-1].Z != foundPath[i].Z && !p.connectors[Node3D{X: foundPath[i].X, Y: foundPath[i].Y}] {
+			t.Error("floor should only change at a connector: ", foundPath[i])
+		}
+	}
+
+	// out of bounds
+	if _, err = p.FindPath(startNode, Node3D{X: 0, Y: 0, Z: 2}); err != ErrOutOfBounds {
+		t.Error("end node should be out of bounds", err)
+	}
+
+	if _, err = NewPathFinder3D(Config3D{GridWidth: 5, GridHeight: 5}); err == nil {
+		t.Error("GridDepth 0 should be invalid")
+	}
+}
+
+func TestManhattanDistance3D(t *testing.T) {
+	if ManhattanDistance3D(Node3D{X: 0, Y: 0, Z: 0}, Node3D{X: 1, Y: -2, Z: 3}) != 6 {
+		t.Error("should be 6")
+	}
+}
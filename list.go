@@ -2,7 +2,16 @@ package astar
 
 import "errors"
 
+// ErrEmptyList is returned by GetMinFNode of a List or a Heap without nodes
+var ErrEmptyList = errors.New("no node found")
+
 // List represents a list of nodes
+// in the order they were added, nodes are identified by their X and Y coordinates
+//
+// All lookups walk the whole slice, so it fits small node sets and own searches
+// on small graphs. The PathFinder keeps its open list in a Heap
+// and its closed list in a NodeSet, which are faster for big grids.
+// The zero value is an empty list ready to use
 type List struct {
 	nodes []Node
 }
@@ -12,18 +21,20 @@ func NewList() *List {
 	return &List{}
 }
 
-// Add one or more nodes to the list
+// Add one or more nodes to the end of the list
+// nodes with the same coordinates are not merged, use Update to replace a node
 func (l *List) Add(nodes ...Node) {
 	l.nodes = append(l.nodes, nodes...)
 }
 
 // All returns the full list of nodes
+// the slice is shared with the list and changes with it
 func (l *List) All() []Node {
 	return l.nodes
 }
 
-// Remove a node from the list
-// if the node is not found we do nothing
+// Remove the first node with the coordinates of removeNode from the list
+// if the node is not found we do nothing, the order of the other nodes is kept
 func (l *List) Remove(removeNode Node) {
 	index := l.GetIndex(removeNode)
 	if index >= 0 {
@@ -31,6 +42,15 @@ func (l *List) Remove(removeNode Node) {
 	}
 }
 
+// Update replaces the node with the same coordinates by updateNode
+// if the node is not found we do nothing
+func (l *List) Update(updateNode Node) {
+	index := l.GetIndex(updateNode)
+	if index >= 0 {
+		l.nodes[index] = updateNode
+	}
+}
+
 // GetIndex returns the index of the node in the list
 // if the node is not found the return value is -1
 func (l *List) GetIndex(searchNode Node) int {
@@ -42,11 +62,25 @@ func (l *List) GetIndex(searchNode Node) int {
 	return -1
 }
 
+// Get returns the first node with the coordinates of searchNode
+func (l *List) Get(searchNode Node) (Node, bool) {
+	index := l.GetIndex(searchNode)
+	if index < 0 {
+		return Node{}, false
+	}
+	return l.nodes[index], true
+}
+
 // Contains check if a node is in the list
 func (l *List) Contains(searchNode Node) bool {
 	return l.GetIndex(searchNode) >= 0
 }
 
+// Len returns the number of nodes in the list
+func (l *List) Len() int {
+	return len(l.nodes)
+}
+
 // IsEmpty returns if the nodes list has nodes or not
 func (l *List) IsEmpty() bool {
 	return len(l.nodes) == 0
@@ -58,7 +92,7 @@ func (l *List) Clear() {
 }
 
 // GetIndexOfMinF returns the index of the nodes list
-// with the smallest node.F value
+// with the smallest node.F value, the first one if several nodes share it
 //
 // if no node is found it returns -1
 func (l *List) GetIndexOfMinF() int {
@@ -74,10 +108,11 @@ func (l *List) GetIndexOfMinF() int {
 }
 
 // GetMinFNode returns the node with the smallest node.F value
+// it returns ErrEmptyList if the list is empty, the node stays in the list
 func (l *List) GetMinFNode() (Node, error) {
 	minFIndex := l.GetIndexOfMinF()
 	if minFIndex == -1 {
-		return Node{}, errors.New("no node found")
+		return Node{}, ErrEmptyList
 	}
 	return l.nodes[minFIndex], nil
 }
@@ -1,6 +1,9 @@
 package astar
 
-import "testing"
+import (
+	"errors"
+	"testing"
+)
 
 func TestList_Add(t *testing.T) {
 	nodeA := Node{}
@@ -73,6 +76,32 @@ func TestList_Remove(t *testing.T) {
 	}
 }
 
+func TestList_Update(t *testing.T) {
+	nodeA := Node{X: 0, Y: 0, g: 5}
+	nodeB := Node{X: 0, Y: 1, g: 3}
+
+	list := NewList()
+
+	list.Add(nodeA)
+	list.Add(nodeB)
+
+	list.Update(Node{X: 0, Y: 0, g: 2})
+
+	if len(list.nodes) != 2 {
+		t.Error("should still have 2 nodes")
+	}
+
+	if list.nodes[list.GetIndex(nodeA)].g != 2 {
+		t.Error("nodeA should be updated")
+	}
+
+	// not in the list, nothing happens
+	list.Update(Node{X: 3, Y: 3})
+	if list.Contains(Node{X: 3, Y: 3}) {
+		t.Error("unknown node should not be added")
+	}
+}
+
 func TestList_Clear(t *testing.T) {
 	nodeA := Node{X: 0, Y: 0}
 	nodeB := Node{X: 0, Y: 1, parent: &nodeA}
@@ -144,3 +173,53 @@ func TestList_GetMinF(t *testing.T) {
 		t.Error("we should have an error here")
 	}
 }
+
+func TestList_All(t *testing.T) {
+	var list List
+	if len(list.All()) != 0 {
+		t.Error("zero value should be an empty list")
+	}
+
+	list.Add(Node{X: 2, Y: 0}, Node{X: 1, Y: 0}, Node{X: 1, Y: 0})
+	all := list.All()
+	if len(all) != 3 || all[0].X != 2 || all[1].X != 1 {
+		t.Error("nodes should be kept in the order they were added: ", all)
+	}
+}
+
+func TestList_EdgeCases(t *testing.T) {
+	list := NewList()
+
+	if list.Contains(Node{}) || list.GetIndex(Node{}) != -1 {
+		t.Error("empty list should not contain a node")
+	}
+
+	// removing a node which is not in the list does nothing
+	list.Add(Node{X: 1, Y: 1})
+	list.Remove(Node{X: 2, Y: 2})
+	if len(list.All()) != 1 {
+		t.Error("list should still have 1 node")
+	}
+
+	// only the first node with the coordinates is removed
+	list.Add(Node{X: 3, Y: 3, f: 1}, Node{X: 3, Y: 3, f: 2})
+	list.Remove(Node{X: 3, Y: 3})
+	if index := list.GetIndex(Node{X: 3, Y: 3}); index != 1 || list.All()[index].f != 2 {
+		t.Error("second node with the same coordinates should be kept")
+	}
+
+	// equal F values return the first node
+	list.Clear()
+	list.Add(Node{X: 4, Y: 0, f: 5}, Node{X: 5, Y: 0, f: 5})
+	if node, err := list.GetMinFNode(); err != nil || node.X != 4 {
+		t.Error("first node with the smallest F should be returned", node, err)
+	}
+	if len(list.All()) != 2 {
+		t.Error("GetMinFNode should not remove the node")
+	}
+
+	list.Clear()
+	if _, err := list.GetMinFNode(); !errors.Is(err, ErrEmptyList) {
+		t.Error("empty list should return ErrEmptyList", err)
+	}
+}
@@ -0,0 +1,88 @@
+package astar
+
+// NearestWalkable returns the cell closest to x/y which a search can enter by the rules of CanEnter,
+// e.g. for a click on a wall. The cells are searched in rings of growing grid distance
+// around x/y, obstacles do not stop the rings, and of one ring the cell with the smallest
+// euclidean distance is taken. Coordinates outside of the grid are wrapped with WrapEdges
+// and moved to the nearest border cell otherwise. If no cell can be entered
+// or the grid is Unbounded it returns false
+func (a *PathFinder) NearestWalkable(ctx IContext, x, y int) (Node, bool) {
+	a.obstacleMu.RLock()
+	defer a.obstacleMu.RUnlock()
+	return a.nearestWalkable(ctx, x, y)
+}
+
+// nearestWalkable is NearestWalkable for callers which already hold the read lock of obstacleMu
+func (a *PathFinder) nearestWalkable(ctx IContext, x, y int) (Node, bool) {
+	if a.config.Unbounded {
+		return Node{}, false
+	}
+	originNode := Node{X: x, Y: y}
+	if a.config.WrapEdges {
+		x, y = a.wrapXY(x, y)
+		originNode = Node{X: x, Y: y}
+	} else {
+		x = clampInt(x, a.config.OriginX, a.config.OriginX+a.config.GridWidth-1)
+		y = clampInt(y, a.config.OriginY, a.config.OriginY+a.config.GridHeight-1)
+	}
+
+	offsets := orthogonalOffsets[:]
+	switch {
+	case a.config.GridType == GridHex:
+		offsets = hexOffsets[:]
+	case a.config.AllowDiagonal:
+		offsets = append(offsets[:len(offsets):len(offsets)], diagonalOffsets[:]...)
+	}
+
+	// a breadth first flood over all cells, every layer is one ring
+	visited := make([]bool, a.config.GridWidth*a.config.GridHeight)
+	startIndex, _ := a.cellIndex(x, y)
+	visited[startIndex] = true
+	ring := []Node{{X: x, Y: y}}
+	var nextRing []Node
+	for len(ring) > 0 {
+		var nearestNode Node
+		nearestDist, found := 0, false
+		for _, node := range ring {
+			if !a.canEnter(ctx, node.X, node.Y) {
+				continue
+			}
+			delta := a.stepDelta(originNode, node)
+			if dist := delta[0]*delta[0] + delta[1]*delta[1]; !found || dist < nearestDist {
+				nearestNode, nearestDist, found = node, dist, true
+			}
+		}
+		if found {
+			return nearestNode, true
+		}
+
+		nextRing = nextRing[:0]
+		for _, node := range ring {
+			for _, offset := range offsets {
+				neighborX, neighborY := node.X+offset[0], node.Y+offset[1]
+				if a.config.WrapEdges {
+					neighborX, neighborY = a.wrapXY(neighborX, neighborY)
+				}
+				index, ok := a.cellIndex(neighborX, neighborY)
+				if !ok || visited[index] {
+					continue
+				}
+				visited[index] = true
+				nextRing = append(nextRing, Node{X: neighborX, Y: neighborY})
+			}
+		}
+		ring, nextRing = nextRing, ring
+	}
+	return Node{}, false
+}
+
+// clampInt limits value to the range from min to max
+func clampInt(value, min, max int) int {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
@@ -0,0 +1,111 @@
+package astar
+
+import "testing"
+
+func TestAstar_NearestWalkable(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [ ]
+	// [ ] [O] [O] [O] [ ]   O: ObstacleNode
+	// [ ] [O] [O] [O] [E]   E: EndNode after the snap
+	// [ ] [O] [O] [O] [ ]
+	// [S] [ ] [ ] [ ] [ ]
+
+	var obstacleNodes []Node
+	for x := 1; x <= 3; x++ {
+		for y := 1; y <= 3; y++ {
+			obstacleNodes = append(obstacleNodes, Node{X: x, Y: y})
+		}
+	}
+	a, err := New(Config{GridWidth: 5, GridHeight: 5, InvalidNodes: obstacleNodes})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+
+	if node, ok := a.NearestWalkable(nil, 0, 4); !ok || node.X != 0 || node.Y != 4 {
+		t.Error("a free cell should be its own nearest cell: ", node, ok)
+	}
+	// the ring of distance 2 around the center holds only the middles of the borders
+	if node, ok := a.NearestWalkable(nil, 2, 2); !ok || ManhattanDistance(node, Node{X: 2, Y: 2}) != 2 {
+		t.Error("the nearest cell should be on the second ring: ", node, ok)
+	}
+	if node, ok := a.NearestWalkable(nil, 3, 2); !ok || node.X != 4 || node.Y != 2 {
+		t.Error("the nearest cell should be right of the wall: ", node, ok)
+	}
+	// a click outside of the grid is moved to the border first
+	if node, ok := a.NearestWalkable(nil, 9, 2); !ok || node.X != 4 || node.Y != 2 {
+		t.Error("the nearest cell should be on the right border: ", node, ok)
+	}
+
+	if _, err = a.FindPath(nil, Node{X: 0, Y: 0}, Node{X: 3, Y: 2}); err != ErrEndBlocked {
+		t.Error("the end node should be blocked without the snap", err)
+	}
+	a, err = New(Config{GridWidth: 5, GridHeight: 5, InvalidNodes: obstacleNodes, SnapBlockedEnd: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, reachedExact, err := a.FindPathReached(nil, Node{X: 0, Y: 0}, Node{X: 3, Y: 2})
+	if err != nil {
+		t.Fatal("there should be a path to the snapped end node", err)
+	}
+	if last := foundPath[len(foundPath)-1]; reachedExact || last.X != 4 || last.Y != 2 {
+		t.Error("the path should end right of the wall: ", foundPath, reachedExact)
+	}
+
+	for x := 0; x < 5; x++ {
+		for y := 0; y < 5; y++ {
+			a.AddObstacle(x, y)
+		}
+	}
+	if _, ok := a.NearestWalkable(nil, 2, 2); ok {
+		t.Error("a blocked grid should have no walkable cell")
+	}
+}
+
+func TestAstar_SnapBlockedEndVariants(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [ ]
+	// [ ] [O] [O] [O] [ ]   O: ObstacleNode
+	// [ ] [O] [O] [O] [E]   E: EndNode after the snap
+	// [ ] [O] [O] [O] [ ]
+	// [S] [ ] [ ] [ ] [ ]
+
+	var obstacleNodes []Node
+	for x := 1; x <= 3; x++ {
+		for y := 1; y <= 3; y++ {
+			obstacleNodes = append(obstacleNodes, Node{X: x, Y: y})
+		}
+	}
+	a, err := New(Config{GridWidth: 5, GridHeight: 5, InvalidNodes: obstacleNodes, AllowDiagonal: true, SnapBlockedEnd: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+
+	startNode, endNode := Node{X: 0, Y: 0}, Node{X: 3, Y: 2}
+	variants := map[string]func() ([]Node, error){
+		"FindPathJPS":   func() ([]Node, error) { return a.FindPathJPS(nil, startNode, endNode) },
+		"FindPathTheta": func() ([]Node, error) { return a.FindPathTheta(nil, startNode, endNode) },
+		"FindPathBidirectional": func() ([]Node, error) {
+			return a.FindPathBidirectional(nil, startNode, endNode)
+		},
+		"NewSearch": func() ([]Node, error) {
+			st, err := a.NewSearch(nil, startNode, endNode)
+			if err != nil {
+				return nil, err
+			}
+			for {
+				if done, err := st.Step(); done {
+					return st.Path(), err
+				}
+			}
+		},
+	}
+	for name, findPath := range variants {
+		foundPath, err := findPath()
+		if err != nil {
+			t.Fatal("there should be a path to the snapped end node: ", name, err)
+		}
+		if last := foundPath[len(foundPath)-1]; last.X != 4 || last.Y != 2 {
+			t.Error("the path should end right of the wall: ", name, foundPath)
+		}
+	}
+}
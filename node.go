@@ -9,15 +9,33 @@ import "fmt"
 //
 // With the Weighting value you can set the nodes heavy grade
 // so a node with mud or water are heavier as gras or street
+// it is the extra cost of entering the node, read from the Config.WeightedNodes
+// and from the candidates of Config.Neighbors, the grid neighbors have no own Weighting
 type Node struct {
 	f         int // g + h
 	g         int // 节点层次
 	h         int // 和目标点评估距离
+	tie       int // F和H相同时的比较值
 	X, Y      int
 	Weighting int
 	parent    *Node
 }
 
+// F returns the G + H of the node, the order of the open list
+func (n Node) F() int {
+	return n.f
+}
+
+// G returns the cost of the way from the start node to the node
+func (n Node) G() int {
+	return n.g
+}
+
+// H returns the estimated cost from the node to the goal
+func (n Node) H() int {
+	return n.h
+}
+
 // String returns formatted values of the node
 func (n Node) String() string {
 	return fmt.Sprintf("Node [X:%d Y:%d F:%d G:%d H:%d]", n.X, n.Y, n.f, n.g, n.h)
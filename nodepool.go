@@ -0,0 +1,34 @@
+package astar
+
+// nodeChunkSize is the number of nodes allocated at once by the nodePool
+const nodeChunkSize = 256
+
+// nodePool hands out stable node records for the parent chain of a search
+//
+// The records are allocated in chunks and reused after reset,
+// so repeated searches do not allocate a node per expansion.
+// A nodePool belongs to a single search state and is not safe for concurrent use,
+// the search states are shared between goroutines only through the sync.Pool of the PathFinder
+type nodePool struct {
+	chunks [][]Node
+	used   int // 已分配的节点数
+}
+
+// alloc stores a copy of node and returns its address
+// the address stays valid until reset is called
+func (p *nodePool) alloc(node Node) *Node {
+	chunk, index := p.used/nodeChunkSize, p.used%nodeChunkSize
+	if chunk == len(p.chunks) {
+		p.chunks = append(p.chunks, make([]Node, nodeChunkSize))
+	}
+	p.used++
+
+	record := &p.chunks[chunk][index]
+	*record = node
+	return record
+}
+
+// reset releases all records for reuse, the chunks are kept
+func (p *nodePool) reset() {
+	p.used = 0
+}
@@ -0,0 +1,25 @@
+package astar
+
+import "testing"
+
+func TestNodePool(t *testing.T) {
+	var pool nodePool
+
+	first := pool.alloc(Node{X: 1, Y: 2})
+	// fill more than one chunk
+	for i := 0; i < 2*nodeChunkSize; i++ {
+		pool.alloc(Node{X: i})
+	}
+	if first.X != 1 || first.Y != 2 {
+		t.Error("records should stay valid while the pool grows: ", first)
+	}
+	if len(pool.chunks) != 3 {
+		t.Error("pool should have 3 chunks: ", len(pool.chunks))
+	}
+
+	// the chunks are reused after reset
+	pool.reset()
+	if reused := pool.alloc(Node{X: 5}); reused != first || len(pool.chunks) != 3 {
+		t.Error("reset should reuse the first record")
+	}
+}
@@ -0,0 +1,56 @@
+package astar
+
+// PriorityQueue is the open list of a search, the node with the smallest F leaves it first.
+// Heap and List implement it, Config.OpenListFactory plugs in an own implementation
+//
+// A search adds a node once for its coordinates and replaces it by Update when a cheaper
+// way is found, so Get, Update, Remove and Contains identify the nodes by X and Y.
+// The costs are read with Node.F, Node.G and Node.H, H is the usual tie breaker
+type PriorityQueue interface {
+	Add(nodes ...Node)
+	Get(searchNode Node) (Node, bool)
+	Update(updateNode Node)
+	Remove(removeNode Node)
+	Contains(searchNode Node) bool
+	GetMinFNode() (Node, error)
+	Len() int
+	All() []Node
+}
+
+// queueSet lets a search use a PriorityQueue as its openSet
+type queueSet struct {
+	queue PriorityQueue
+}
+
+func (q *queueSet) push(node Node) {
+	q.queue.Add(node)
+}
+
+func (q *queueSet) get(node Node) (Node, bool) {
+	return q.queue.Get(node)
+}
+
+func (q *queueSet) update(node Node) {
+	q.queue.Update(node)
+}
+
+func (q *queueSet) popMin() (Node, error) {
+	node, err := q.queue.GetMinFNode()
+	if err == nil {
+		q.queue.Remove(node)
+	}
+	return node, err
+}
+
+func (q *queueSet) size() int {
+	return q.queue.Len()
+}
+
+func (q *queueSet) all() []Node {
+	return append([]Node(nil), q.queue.All()...)
+}
+
+// clear drops the queue, the factory creates a new one for the next search
+func (q *queueSet) clear() {
+	q.queue = nil
+}
@@ -0,0 +1,70 @@
+package astar
+
+import "testing"
+
+var (
+	_ PriorityQueue = (*Heap)(nil)
+	_ PriorityQueue = (*List)(nil)
+)
+
+// countingQueue counts the nodes taken from the wrapped List
+type countingQueue struct {
+	List
+	taken int
+}
+
+func (q *countingQueue) Remove(removeNode Node) {
+	q.taken++
+	q.List.Remove(removeNode)
+}
+
+func TestAstar_OpenListFactory(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [E]   S: StartNode
+	// [ ] [ ] [ ] [ ] [ ]   E: EndNode
+	// [ ] [ ] [O] [O] [O]   O: ObstacleNode
+	// [ ] [ ] [ ] [ ] [ ]
+	// [S] [ ] [ ] [ ] [ ]
+
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 4, Y: 4}
+	obstacleNodes := []Node{{X: 2, Y: 2}, {X: 3, Y: 2}, {X: 4, Y: 2}}
+
+	a, err := New(Config{GridWidth: 5, GridHeight: 5, InvalidNodes: obstacleNodes})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	_, wantCost, err := a.FindPathWithCost(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+
+	var queues []*countingQueue
+	a, err = New(Config{GridWidth: 5, GridHeight: 5, InvalidNodes: obstacleNodes, OpenListFactory: func() PriorityQueue {
+		queue := &countingQueue{}
+		queues = append(queues, queue)
+		return queue
+	}})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	for i := 0; i < 2; i++ {
+		foundPath, cost, err := a.FindPathWithCost(nil, startNode, endNode)
+		if err != nil {
+			t.Fatal("there should be a path", err)
+		}
+		if cost != wantCost || foundPath[len(foundPath)-1].G() != wantCost {
+			t.Error("the own queue should find the same cost: ", cost, wantCost)
+		}
+	}
+	// every search gets a new queue
+	if len(queues) != 2 || queues[0].taken == 0 || queues[0] == queues[1] {
+		t.Error("every search should take its nodes from an own queue: ", len(queues))
+	}
+
+	if _, err := New(Config{GridWidth: 5, GridHeight: 5, BucketOpenList: true, OpenListFactory: func() PriorityQueue {
+		return NewHeap()
+	}}); err == nil {
+		t.Error("BucketOpenList and OpenListFactory should be an error")
+	}
+}
@@ -0,0 +1,146 @@
+package astar
+
+// SameRegion checks if both cells are walkable and connected by the static obstacles,
+// the InvalidNodes, the added obstacles and the BlockedEdges. The blocks of an IContext
+// are not known to it, so two cells of the same region may still have no path between them
+//
+// The regions are computed once by a union-find over the walkable cells and cached
+// until AddObstacle or RemoveObstacle change the grid
+func (a *PathFinder) SameRegion(nodeA, nodeB Node) bool {
+	a.obstacleMu.RLock()
+	defer a.obstacleMu.RUnlock()
+	return a.sameRegion(nodeA, nodeB)
+}
+
+// ComputeRegions labels every walkable cell with the id of its connected region
+// and returns the number of regions, the ids run from 0 to the count - 1
+//
+// It respects the same obstacles as SameRegion. The labels are cached until
+// AddObstacle or RemoveObstacle change the grid, RegionOf and RegionCount compute them
+// on demand as well, so calling it up front only moves the work out of the first lookup
+func (a *PathFinder) ComputeRegions() int {
+	a.obstacleMu.RLock()
+	defer a.obstacleMu.RUnlock()
+	_, count := a.regionLabels()
+	return count
+}
+
+// RegionOf returns the id of the region of the cell,
+// -1 if the cell is blocked or out of bounds
+func (a *PathFinder) RegionOf(x, y int) int {
+	a.obstacleMu.RLock()
+	defer a.obstacleMu.RUnlock()
+	labels, _ := a.regionLabels()
+	index, ok := a.cellIndex(x, y)
+	if !ok {
+		return -1
+	}
+	return labels[index]
+}
+
+// RegionCount returns the number of connected regions,
+// more than one region means there are pockets which cannot reach each other
+func (a *PathFinder) RegionCount() int {
+	return a.ComputeRegions()
+}
+
+// sameRegion is SameRegion for callers which already hold obstacleMu
+func (a *PathFinder) sameRegion(nodeA, nodeB Node) bool {
+	labels, _ := a.regionLabels()
+	indexA, okA := a.cellIndex(nodeA.X, nodeA.Y)
+	indexB, okB := a.cellIndex(nodeB.X, nodeB.Y)
+	if !okA || !okB || labels[indexA] < 0 {
+		return false
+	}
+	return labels[indexA] == labels[indexB]
+}
+
+// regionLabels returns the region id of every cell, -1 for blocked cells,
+// and the number of regions. They are built on the first call after the obstacles changed
+func (a *PathFinder) regionLabels() ([]int, int) {
+	a.regionMu.Lock()
+	defer a.regionMu.Unlock()
+	if a.regions == nil {
+		a.regions, a.regionCount = a.buildRegions()
+	}
+	return a.regions, a.regionCount
+}
+
+// invalidateRegions drops the cached regions, the caller holds the write lock of obstacleMu
+func (a *PathFinder) invalidateRegions() {
+	a.regionMu.Lock()
+	a.regions = nil
+	a.regionCount = 0
+	a.regionMu.Unlock()
+}
+
+// buildRegions joins every walkable cell with its neighbors by a union-find
+// and numbers the regions in the order of their first cell
+func (a *PathFinder) buildRegions() ([]int, int) {
+	width, height := a.config.GridWidth, a.config.GridHeight
+	parents := make([]int, width*height)
+	for i := range parents {
+		parents[i] = -1
+	}
+
+	find := func(index int) int {
+		for parents[index] != index {
+			// path halving keeps the trees flat
+			parents[index] = parents[parents[index]]
+			index = parents[index]
+		}
+		return index
+	}
+
+	for gridY := 0; gridY < height; gridY++ {
+		for gridX := 0; gridX < width; gridX++ {
+			x, y := gridX+a.config.OriginX, gridY+a.config.OriginY
+			if a.isWalkable(nil, x, y) {
+				parents[gridY*width+gridX] = gridY*width + gridX
+			}
+		}
+	}
+
+	var neighbors []Node
+	for index, parent := range parents {
+		if parent < 0 {
+			continue
+		}
+		node := Node{X: index%width + a.config.OriginX, Y: index/width + a.config.OriginY}
+		neighbors = a.appendNeighborNodes(nil, neighbors[:0], &node, nil)
+		for _, neighbor := range neighbors {
+			neighborIndex, _ := a.cellIndex(neighbor.X, neighbor.Y)
+			if rootA, rootB := find(index), find(neighborIndex); rootA != rootB {
+				parents[rootB] = rootA
+			}
+		}
+	}
+
+	// ids holds the region id of every root cell
+	labels, ids := make([]int, len(parents)), make([]int, len(parents))
+	for i := range ids {
+		ids[i] = -1
+	}
+	count := 0
+	for index, parent := range parents {
+		if parent < 0 {
+			labels[index] = -1
+			continue
+		}
+		root := find(index)
+		if ids[root] < 0 {
+			ids[root] = count
+			count++
+		}
+		labels[index] = ids[root]
+	}
+	return labels, count
+}
+
+// cellIndex returns the index of the cell in a slice of all grid cells
+func (a *PathFinder) cellIndex(x, y int) (int, bool) {
+	if !a.inBounds(x, y) {
+		return 0, false
+	}
+	return (y-a.config.OriginY)*a.config.GridWidth + (x - a.config.OriginX), true
+}
@@ -0,0 +1,103 @@
+package astar
+
+import "testing"
+
+func TestAstar_SameRegion(t *testing.T) {
+
+	// [ ] [O] [ ] [ ]   O: ObstacleNode
+	// [ ] [O] [ ] [ ]
+	// [ ] [O] [ ] [ ]
+
+	var obstacleNodes []Node
+	for y := 0; y < 3; y++ {
+		obstacleNodes = append(obstacleNodes, Node{X: 1, Y: y})
+	}
+
+	a, err := New(Config{GridWidth: 4, GridHeight: 3, InvalidNodes: obstacleNodes})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	left, right := Node{X: 0, Y: 0}, Node{X: 3, Y: 2}
+
+	if a.SameRegion(left, right) {
+		t.Error("the wall should split the grid")
+	}
+	if !a.SameRegion(right, Node{X: 2, Y: 0}) {
+		t.Error("cells right of the wall should share a region")
+	}
+	if a.SameRegion(left, Node{X: 1, Y: 0}) || a.SameRegion(left, Node{X: -1, Y: 0}) {
+		t.Error("blocked and out of bounds cells have no region")
+	}
+
+	// the cache is dropped when the obstacles change
+	a.RemoveObstacle(1, 1)
+	if !a.SameRegion(left, right) {
+		t.Error("the door should join both regions")
+	}
+	a.AddObstacle(1, 1)
+	if a.SameRegion(left, right) {
+		t.Error("the closed door should split the grid again")
+	}
+}
+
+func TestAstar_FindPathRegionCheck(t *testing.T) {
+
+	// [ ] [O] [ ]   S: StartNode
+	// [S] [O] [E]   E: EndNode
+	// [ ] [O] [ ]   O: ObstacleNode
+
+	startNode := Node{X: 0, Y: 1}
+	endNode := Node{X: 2, Y: 1}
+	obstacleNodes := []Node{{X: 1, Y: 0}, {X: 1, Y: 1}, {X: 1, Y: 2}}
+
+	a, err := New(Config{GridWidth: 3, GridHeight: 3, InvalidNodes: obstacleNodes, RegionCheck: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, stats, err := a.FindPathStats(nil, startNode, endNode)
+	if err != ErrorNoPath {
+		t.Error("there should be no path", foundPath, err)
+	}
+	if stats.Expanded != 0 {
+		t.Error("search should not expand any node: ", stats.Expanded)
+	}
+
+	a.RemoveObstacle(1, 2)
+	if _, err = a.FindPath(nil, startNode, endNode); err != nil {
+		t.Error("there should be a path through the door", err)
+	}
+}
+
+func TestAstar_ComputeRegions(t *testing.T) {
+
+	// [ ] [O] [ ] [O] [ ]   O: ObstacleNode
+	// [ ] [O] [ ] [O] [O]
+	// [ ] [O] [ ] [ ] [ ]
+
+	obstacleNodes := []Node{{X: 1, Y: 0}, {X: 1, Y: 1}, {X: 1, Y: 2}, {X: 3, Y: 1}, {X: 3, Y: 2}, {X: 4, Y: 1}}
+
+	a, err := New(Config{GridWidth: 5, GridHeight: 3, InvalidNodes: obstacleNodes})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if count := a.ComputeRegions(); count != 3 {
+		t.Error("grid should have 3 regions: ", count)
+	}
+
+	// the ids follow the order of the first cell of every region
+	if a.RegionOf(0, 2) != 0 || a.RegionOf(2, 0) != 1 || a.RegionOf(4, 0) != 1 || a.RegionOf(4, 2) != 2 {
+		t.Error("wrong region ids: ", a.RegionOf(0, 2), a.RegionOf(2, 0), a.RegionOf(4, 0), a.RegionOf(4, 2))
+	}
+	if a.RegionOf(1, 1) != -1 || a.RegionOf(5, 0) != -1 {
+		t.Error("blocked and out of bounds cells have no region")
+	}
+
+	// the pocket in the top right corner is joined
+	a.RemoveObstacle(3, 2)
+	if count := a.RegionCount(); count != 2 {
+		t.Error("grid should have 2 regions: ", count)
+	}
+	if a.RegionOf(4, 2) != a.RegionOf(2, 0) {
+		t.Error("pocket should belong to the middle region")
+	}
+}
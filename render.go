@@ -0,0 +1,47 @@
+package astar
+
+import "strings"
+
+// RenderPath draws the grid of the config as text for debugging
+// '#' is an invalid node, '.' an open cell, '*' a node of the path,
+// 'S' and 'E' are the first and the last node of the path
+//
+// The top row is the highest Y, so the drawing keeps the "bottom left" origin.
+// Path nodes outside of the grid are not drawn,
+// a config without a positive GridWidth and GridHeight draws the empty string
+func RenderPath(config Config, path []Node) string {
+	if config.GridWidth <= 0 || config.GridHeight <= 0 {
+		return ""
+	}
+	cells := make([][]byte, config.GridHeight)
+	for y := range cells {
+		cells[y] = []byte(strings.Repeat(".", config.GridWidth))
+	}
+
+	set := func(node Node, c byte) {
+		x, y := node.X-config.OriginX, node.Y-config.OriginY
+		if x >= 0 && y >= 0 && x < config.GridWidth && y < config.GridHeight {
+			cells[y][x] = c
+		}
+	}
+	for _, node := range config.InvalidNodes {
+		set(node, '#')
+	}
+	for i, node := range path {
+		switch i {
+		case 0:
+			set(node, 'S')
+		case len(path) - 1:
+			set(node, 'E')
+		default:
+			set(node, '*')
+		}
+	}
+
+	var sb strings.Builder
+	for y := config.GridHeight - 1; y >= 0; y-- {
+		sb.Write(cells[y])
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
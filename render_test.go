@@ -0,0 +1,40 @@
+package astar
+
+import "testing"
+
+func TestRenderPath(t *testing.T) {
+	config := Config{
+		GridWidth:    4,
+		GridHeight:   3,
+		InvalidNodes: []Node{{X: 1, Y: 0}, {X: 1, Y: 1}},
+	}
+	a, err := New(config)
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, err := a.FindPath(nil, Node{X: 0, Y: 0}, Node{X: 2, Y: 0})
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+
+	want := "" +
+		"***.\n" +
+		"*#*.\n" +
+		"S#E.\n"
+	if got := RenderPath(config, foundPath); got != want {
+		t.Errorf("wrong rendering:\n%s\nwant:\n%s", got, want)
+	}
+
+	// shifted grid without a path
+	config = Config{GridWidth: 2, GridHeight: 2, OriginX: 5, OriginY: 5, InvalidNodes: []Node{{X: 6, Y: 6}}}
+	if got := RenderPath(config, nil); got != ".#\n..\n" {
+		t.Errorf("wrong rendering:\n%s", got)
+	}
+
+	// sizes which New would reject draw nothing instead of panicking
+	for _, config := range []Config{{GridWidth: 3, GridHeight: -1}, {GridWidth: -2, GridHeight: 3}, {Unbounded: true}} {
+		if got := RenderPath(config, []Node{{X: 0, Y: 0}}); got != "" {
+			t.Errorf("wrong rendering of %dx%d:\n%s", config.GridWidth, config.GridHeight, got)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package astar
+
+// NodeSet represents an unordered set of nodes
+// keyed by the node coordinates, Add and Contains are O(1)
+type NodeSet struct {
+	nodes map[[2]int]Node
+}
+
+// NewNodeSet creates a new set
+func NewNodeSet() *NodeSet {
+	return &NodeSet{}
+}
+
+// Add one or more nodes to the set
+// if a node with the same coordinates is already stored it gets replaced
+func (s *NodeSet) Add(nodes ...Node) {
+	if s.nodes == nil {
+		s.nodes = make(map[[2]int]Node, len(nodes))
+	}
+	for _, node := range nodes {
+		s.nodes[nodeKey(node)] = node
+	}
+}
+
+// All returns the nodes of the set in no particular order
+func (s *NodeSet) All() []Node {
+	nodes := make([]Node, 0, len(s.nodes))
+	for _, node := range s.nodes {
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// Get returns the stored node with the coordinates of searchNode
+func (s *NodeSet) Get(searchNode Node) (Node, bool) {
+	node, ok := s.nodes[nodeKey(searchNode)]
+	return node, ok
+}
+
+// Remove a node from the set
+// if the node is not found we do nothing
+func (s *NodeSet) Remove(removeNode Node) {
+	delete(s.nodes, nodeKey(removeNode))
+}
+
+// Contains check if a node is in the set
+func (s *NodeSet) Contains(searchNode Node) bool {
+	_, ok := s.nodes[nodeKey(searchNode)]
+	return ok
+}
+
+// Len returns the number of nodes in the set
+func (s *NodeSet) Len() int {
+	return len(s.nodes)
+}
+
+// IsEmpty returns if the set has nodes or not
+func (s *NodeSet) IsEmpty() bool {
+	return len(s.nodes) == 0
+}
+
+// Clear removes all nodes from the set
+// the allocated storage is kept so the set can be filled again without allocations
+func (s *NodeSet) Clear() {
+	for key := range s.nodes {
+		delete(s.nodes, key)
+	}
+}
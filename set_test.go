@@ -0,0 +1,68 @@
+package astar
+
+import "testing"
+
+func TestNodeSet(t *testing.T) {
+	nodeA := Node{X: 0, Y: 0}
+	nodeB := Node{X: 0, Y: 1, parent: &nodeA}
+
+	set := NewNodeSet()
+
+	if set.Contains(nodeA) || !set.IsEmpty() {
+		t.Error("new set should be empty")
+	}
+
+	set.Add(nodeA, nodeB)
+
+	if !set.Contains(nodeA) || !set.Contains(nodeB) {
+		t.Error("should have nodeA and nodeB")
+	}
+
+	// same coordinates replace the stored node
+	set.Add(Node{X: 0, Y: 0, g: 4})
+	if set.Len() != 2 || len(set.All()) != 2 {
+		t.Error("should have 2 nodes")
+	}
+	if node, ok := set.Get(nodeA); !ok || node.g != 4 {
+		t.Error("nodeA should be replaced", node)
+	}
+
+	set.Remove(nodeA)
+	set.Remove(nodeA) // try removing it twice
+	if set.Contains(nodeA) {
+		t.Error("nodeA should not exist")
+	}
+	if !set.Contains(nodeB) {
+		t.Error("nodeB should still be there")
+	}
+
+	set.Clear()
+	if !set.IsEmpty() {
+		t.Error("IsEmpty should be true")
+	}
+}
+
+func TestNodeSetNegativeCoordinates(t *testing.T) {
+	// y*width+x with a width of 4 maps both nodes to -1
+	nodeA := Node{X: -1, Y: 0}
+	nodeB := Node{X: 3, Y: -1}
+
+	set := NewNodeSet()
+	set.Add(nodeA)
+	if set.Contains(nodeB) {
+		t.Error("nodeB should not collide with nodeA")
+	}
+	set.Add(nodeB)
+	if set.Len() != 2 {
+		t.Error("should have 2 nodes")
+	}
+
+	h := NewHeap()
+	h.Add(Node{X: -1, Y: 0, f: 2}, Node{X: 3, Y: -1, f: 1})
+	if h.Len() != 2 {
+		t.Error("the heap should keep both nodes")
+	}
+	if node, _ := h.GetMinFNode(); node.X != 3 || node.Y != -1 {
+		t.Error("nodeB should be the smallest node: ", node)
+	}
+}
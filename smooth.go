@@ -0,0 +1,68 @@
+package astar
+
+// SmoothPath removes the nodes of a path which can be skipped
+// because there is a clear straight line between the nodes before and after them
+//
+// The line of sight is checked with the Bresenham algorithm against the same
+// obstacles FindPath uses: the grid bounds, the InvalidNodes and ctx.IsInBlock.
+// The first and the last node are always kept, the order of the path is not changed.
+// Hex grids have no straight lines between cells, their paths are returned unchanged,
+// like the paths of a grid with BlockedEdges or WrapEdges
+func (a *PathFinder) SmoothPath(ctx IContext, path []Node) []Node {
+	if len(path) <= 2 || a.config.GridType == GridHex || len(a.config.BlockedEdges) > 0 || a.config.WrapEdges {
+		return append([]Node(nil), path...)
+	}
+
+	a.obstacleMu.RLock()
+	defer a.obstacleMu.RUnlock()
+
+	smoothPath := []Node{path[0]}
+	anchor := path[0]
+	for i := 2; i < len(path); i++ {
+		if !a.lineOfSight(ctx, anchor, path[i]) {
+			anchor = path[i-1]
+			smoothPath = append(smoothPath, anchor)
+		}
+	}
+	return append(smoothPath, path[len(path)-1])
+}
+
+// lineOfSight checks if every cell on the Bresenham line between the nodes is walkable
+//
+// a diagonal step of the line is rejected if it squeezes between two blocked cells,
+// with DisallowCornerCutting already one blocked cell beside it rejects the step
+func (a *PathFinder) lineOfSight(ctx IContext, from, to Node) bool {
+	x, y := from.X, from.Y
+	dx, dy := absInt(to.X-from.X), -absInt(to.Y-from.Y)
+	sx, sy := sign(to.X-from.X), sign(to.Y-from.Y)
+	e := dx + dy
+
+	for {
+		if !a.isWalkable(ctx, x, y) {
+			return false
+		}
+		if x == to.X && y == to.Y {
+			return true
+		}
+
+		stepX, stepY := 0, 0
+		if 2*e >= dy {
+			e += dy
+			stepX = sx
+		}
+		if 2*e <= dx {
+			e += dx
+			stepY = sy
+		}
+
+		if stepX != 0 && stepY != 0 {
+			blockedX := !a.isWalkable(ctx, x+stepX, y)
+			blockedY := !a.isWalkable(ctx, x, y+stepY)
+			if (blockedX && blockedY) || (a.config.DisallowCornerCutting && (blockedX || blockedY)) {
+				return false
+			}
+		}
+
+		x, y = x+stepX, y+stepY
+	}
+}
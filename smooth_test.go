@@ -0,0 +1,100 @@
+package astar
+
+import "testing"
+
+func TestAstar_SmoothPath(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [ ] [ ] [E]   S: StartNode
+	// [ ] [ ] [ ] [ ] [ ] [ ] [ ]   E: EndNode
+	// [ ] [ ] [O] [O] [O] [ ] [ ]   O: ObstacleNode
+	// [ ] [ ] [O] [ ] [ ] [ ] [ ]
+	// [ ] [ ] [O] [ ] [ ] [ ] [ ]
+	// [ ] [ ] [ ] [ ] [ ] [ ] [ ]
+	// [S] [ ] [ ] [ ] [ ] [ ] [ ]
+
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 6, Y: 6}
+	obstacleNodes := []Node{
+		{X: 2, Y: 2},
+		{X: 2, Y: 3},
+		{X: 2, Y: 4},
+		{X: 3, Y: 4},
+		{X: 4, Y: 4},
+	}
+
+	a, err := New(Config{GridWidth: 7, GridHeight: 7, InvalidNodes: obstacleNodes})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, err := a.FindPath(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+
+	smoothPath := a.SmoothPath(nil, foundPath)
+	if len(smoothPath) >= len(foundPath) {
+		t.Error("smooth path should have fewer nodes: ", smoothPath)
+	}
+	if smoothPath[0] != foundPath[0] || smoothPath[len(smoothPath)-1] != foundPath[len(foundPath)-1] {
+		t.Error("start and end node should be kept: ", smoothPath)
+	}
+
+	// every segment is a clear line
+	for i := 1; i < len(smoothPath); i++ {
+		if !a.lineOfSight(nil, smoothPath[i-1], smoothPath[i]) {
+			t.Error("segment crosses an obstacle: ", smoothPath[i-1], smoothPath[i])
+		}
+	}
+
+	// the obstacles still block the direct line
+	if len(smoothPath) == 2 {
+		t.Error("the direct line crosses the wall: ", smoothPath)
+	}
+
+	// a straight path collapses to its end points
+	straightPath := []Node{{X: 0, Y: 6}, {X: 1, Y: 6}, {X: 2, Y: 6}, {X: 3, Y: 6}}
+	if smoothPath = a.SmoothPath(nil, straightPath); len(smoothPath) != 2 {
+		t.Error("straight path should only keep its end points: ", smoothPath)
+	}
+
+	// short paths are returned as they are
+	if smoothPath = a.SmoothPath(nil, foundPath[:2]); len(smoothPath) != 2 {
+		t.Error("short path should not change: ", smoothPath)
+	}
+}
+
+func TestAstar_LineOfSight(t *testing.T) {
+
+	// [ ] [ ] [ ]
+	// [ ] [O] [ ]   O: ObstacleNode
+	// [ ] [ ] [O]
+
+	a, err := New(Config{GridWidth: 3, GridHeight: 3, InvalidNodes: []Node{{X: 1, Y: 1}, {X: 2, Y: 0}}})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+
+	if !a.lineOfSight(nil, Node{X: 0, Y: 0}, Node{X: 0, Y: 2}) {
+		t.Error("vertical line should be clear")
+	}
+	if a.lineOfSight(nil, Node{X: 0, Y: 0}, Node{X: 2, Y: 2}) {
+		t.Error("diagonal line crosses the obstacle")
+	}
+	// squeezes between (1,1) and (2,0)
+	if a.lineOfSight(nil, Node{X: 1, Y: 0}, Node{X: 2, Y: 1}) {
+		t.Error("line should not squeeze between two obstacles")
+	}
+	// passes one blocked corner
+	if !a.lineOfSight(nil, Node{X: 0, Y: 1}, Node{X: 1, Y: 2}) {
+		t.Error("line may pass a single blocked corner")
+	}
+	a.config.DisallowCornerCutting = true
+	if a.lineOfSight(nil, Node{X: 0, Y: 1}, Node{X: 1, Y: 2}) {
+		t.Error("line should not cut the corner")
+	}
+	// the context blocks as well
+	ctx := newContext(0, 0, 0, []Node{{X: 0, Y: 1}})
+	if a.lineOfSight(ctx, Node{X: 0, Y: 0}, Node{X: 0, Y: 2}) {
+		t.Error("line should be blocked by the context")
+	}
+}
@@ -0,0 +1,108 @@
+package astar
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Stepper runs a search one expansion at a time
+// e.g. to render the open and the closed list after every step of the algorithm
+//
+// It expands the nodes in the same order as FindPath, which runs the same steps in a loop.
+// Every Step holds the obstacles for its expansion only, obstacles added between
+// two steps are seen by the following expansions. The last Step sets Steps and runs
+// the check of Config.CheckAdmissibility like FindPath. A Stepper is not safe for concurrent use
+type Stepper struct {
+	finder   *PathFinder
+	s        *search
+	ctx      IContext
+	opts     searchOptions
+	endNode  Node    // 吸附后的终点
+	visited  *[]Node // 设置CheckAdmissibility时记录展开的节点
+	current  Node    // 最后展开的节点
+	expanded bool    // 至少展开了一个节点
+	done     bool    // 搜索结束
+	path     []Node  // 找到的路径
+	err      error   // 搜索结束时的错误
+}
+
+// NewSearch prepares a search from the start to the end node, the start node is
+// the only node of the open list. The endpoints are snapped and checked like in FindPath,
+// if they are out of bounds or blocked it returns the error of FindPath
+func (a *PathFinder) NewSearch(ctx IContext, startNode, endNode Node) (*Stepper, error) {
+	a.obstacleMu.RLock()
+	defer a.obstacleMu.RUnlock()
+
+	endNode = a.snapEnd(ctx, endNode)
+	st := &Stepper{finder: a, s: &search{finder: a}, ctx: ctx, endNode: endNode}
+	st.opts = searchOptions{
+		maxSteps: StepsNoLimit,
+		onExpand: func(node Node) {
+			st.current, st.expanded = node, true
+		},
+	}
+	if err := a.checkSearch(ctx, startNode, endNode, st.opts); err != nil {
+		return nil, err
+	}
+	if a.config.CheckAdmissibility {
+		st.visited = new([]Node)
+		st.opts.onExpand = recordExpanded(st.visited, st.opts.onExpand)
+	}
+	a.startSearch(st.s, startNode, endNode, st.opts)
+	return st, nil
+}
+
+// Step moves the node with the smallest F from the open to the closed list
+// and opens its neighbors
+//
+// done reports that the search is over, either the goal was reached and Path returns
+// the path or err is the error FindPath would return. Once done, Step does nothing
+func (st *Stepper) Step() (done bool, err error) {
+	if st.done {
+		return true, st.err
+	}
+
+	st.finder.obstacleMu.RLock()
+	defer st.finder.obstacleMu.RUnlock()
+	st.done, st.path, st.err = st.s.step(context.Background(), st.ctx, st.opts)
+	if !st.done {
+		return false, nil
+	}
+	if st.err == nil && st.visited != nil && len(st.path) > 0 {
+		st.err = st.finder.checkAdmissibility(context.Background(), st.ctx, *st.visited, st.path[len(st.path)-1], st.endNode)
+	}
+	atomic.StoreInt64(&st.finder.lastSteps, int64(st.s.steps))
+	return st.done, st.err
+}
+
+// Current returns the node expanded by the last Step
+// ok is false before the first Step
+func (st *Stepper) Current() (node Node, ok bool) {
+	node = st.current
+	node.parent = nil
+	return node, st.expanded
+}
+
+// OpenNodes returns the nodes of the open list in no particular order
+func (st *Stepper) OpenNodes() []Node {
+	return detachNodes(st.s.openList.all())
+}
+
+// ClosedNodes returns the expanded nodes in no particular order
+func (st *Stepper) ClosedNodes() []Node {
+	return detachNodes(st.s.closedList.All())
+}
+
+// Path returns the path in start to goal order once the goal was reached, otherwise nil
+func (st *Stepper) Path() []Node {
+	return st.path
+}
+
+// detachNodes clears the parents of the copied nodes,
+// so the caller cannot reach the state of the search
+func detachNodes(nodes []Node) []Node {
+	for i := range nodes {
+		nodes[i].parent = nil
+	}
+	return nodes
+}
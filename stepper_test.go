@@ -0,0 +1,94 @@
+package astar
+
+import "testing"
+
+func TestAstar_NewSearch(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [E]   S: StartNode
+	// [ ] [O] [O] [O] [ ]   E: EndNode
+	// [ ] [ ] [ ] [O] [ ]   O: ObstacleNode
+	// [S] [ ] [ ] [O] [ ]
+
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 4, Y: 3}
+	obstacleNodes := []Node{{X: 1, Y: 2}, {X: 2, Y: 2}, {X: 3, Y: 2}, {X: 3, Y: 1}, {X: 3, Y: 0}}
+
+	a, err := New(Config{GridWidth: 5, GridHeight: 4, InvalidNodes: obstacleNodes})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	wantPath, explored, err := a.FindPathDebug(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+
+	st, err := a.NewSearch(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if _, ok := st.Current(); ok {
+		t.Error("no node should be expanded before the first step")
+	}
+	if open := st.OpenNodes(); len(open) != 1 || open[0] != startNode {
+		t.Error("the start node should be the only open node: ", open)
+	}
+
+	steps := 0
+	for {
+		done, err := st.Step()
+		if err != nil {
+			t.Fatal("there should be a path", err)
+		}
+		current, ok := st.Current()
+		if !ok || steps >= len(explored) || current.X != explored[steps].X || current.Y != explored[steps].Y {
+			t.Fatal("steps should expand the nodes of FindPath: ", current, steps)
+		}
+		steps++
+		if len(st.ClosedNodes()) != steps {
+			t.Error("every step should close one node: ", st.ClosedNodes())
+		}
+		for _, node := range st.OpenNodes() {
+			if node.parent != nil {
+				t.Error("open nodes should not expose their parents: ", node)
+			}
+		}
+		if done {
+			break
+		}
+		if st.Path() != nil {
+			t.Error("there should be no path before the goal is reached")
+		}
+	}
+
+	if steps != len(explored) || len(st.Path()) != len(wantPath) {
+		t.Error("stepper should find the path of FindPath: ", st.Path(), wantPath)
+	}
+	if a.Steps() != steps {
+		t.Error("the last step should set the steps of the search: ", a.Steps(), steps)
+	}
+	if done, err := st.Step(); !done || err != nil || len(st.ClosedNodes()) != steps {
+		t.Error("step after the end should do nothing", done, err)
+	}
+
+	// blocked end node
+	if _, err = a.NewSearch(nil, startNode, Node{X: 3, Y: 0}); err != ErrEndBlocked {
+		t.Error("end node should be blocked", err)
+	}
+
+	// no path
+	a.AddObstacle(0, 2)
+	a.AddObstacle(4, 2)
+	st, err = a.NewSearch(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	for {
+		done, err := st.Step()
+		if done {
+			if err != ErrorNoPath {
+				t.Error("there should be no path", err)
+			}
+			break
+		}
+	}
+}
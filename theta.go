@@ -0,0 +1,77 @@
+package astar
+
+import "math"
+
+// FindPathTheta starts the Theta* any-angle search for the given start and end node
+// The path is ordered from start to goal like the one of FindPath, but it only
+// holds the turning points, consecutive nodes are connected by a clear straight line
+//
+// When a neighbor is opened, its parent is set to the parent of the current node
+// if there is a line of sight between them, so the path is not bound to the 8 grid directions.
+// G is the euclidean length of the segments, H the euclidean distance to the end node.
+// If the grid is not uniform-cost (AllowDiagonal is not set, WeightedNodes, WeightedRegions, CostFunc, MoveCost or Neighbors are used)
+// or AllowBlockedEndpoints, BlockedEdges, WrapEdges, TurnPenalty or CellWidth and CellHeight are set
+// or GoalRadius without an IContext or ctx is an ITimedContext it falls back to FindPath. ctx.IsNearEnough is not used, the search ends at the exact end node
+func (a *PathFinder) FindPathTheta(ctx IContext, startNode, endNode Node) ([]Node, error) {
+	if !a.config.AllowDiagonal || len(a.config.WeightedNodes) > 0 || len(a.config.WeightedRegions) > 0 || a.config.CostFunc != nil || a.config.MoveCost != nil || a.config.Neighbors != nil ||
+		a.config.AllowBlockedEndpoints || len(a.config.BlockedEdges) > 0 || a.config.WrapEdges || a.config.TurnPenalty > 0 ||
+		a.cellCosts != nil || isTimed(ctx) || a.usesGoalRadius(ctx) {
+		return a.FindPath(ctx, startNode, endNode)
+	}
+
+	a.obstacleMu.RLock()
+	defer a.obstacleMu.RUnlock()
+	endNode = a.snapEnd(ctx, endNode)
+
+	if err := a.checkEndpoints(ctx, startNode, endNode, false); err != nil {
+		return nil, err
+	}
+
+	var openList Heap
+	var closedList NodeSet
+	openList.items.tieBreaking = a.config.TieBreaking
+	startNode.parent = nil
+	openList.Add(startNode)
+
+	for !openList.IsEmpty() {
+		currentNode, _ := openList.GetMinFNode()
+		openList.Remove(currentNode)
+		closedList.Add(currentNode)
+
+		if currentNode.X == endNode.X && currentNode.Y == endNode.Y {
+			return a.getNodePath(currentNode), nil
+		}
+
+		for _, neighbor := range a.appendNeighborNodes(ctx, nil, &currentNode, nil) {
+			if closedList.Contains(neighbor) {
+				continue
+			}
+
+			// skip the current node if its parent sees the neighbor
+			if currentNode.parent != nil && a.lineOfSight(ctx, *currentNode.parent, neighbor) {
+				neighbor.parent = currentNode.parent
+			}
+			neighbor.g = neighbor.parent.g + a.lineCost(*neighbor.parent, neighbor)
+			if !a.config.DisableHeuristic {
+				neighbor.h = a.lineCost(neighbor, endNode)
+			}
+			neighbor.f = neighbor.g + a.weightH(neighbor.h)
+
+			openNode, ok := openList.Get(neighbor)
+			if !ok {
+				openList.Add(neighbor)
+			} else if neighbor.g < openNode.g {
+				openList.Update(neighbor)
+			}
+		}
+	}
+
+	return nil, ErrorNoPath
+}
+
+// lineCost returns the euclidean length of the straight line between two nodes
+// scaled by the cost of an orthogonal step
+func (a *PathFinder) lineCost(from, to Node) int {
+	dx, dy := float64(to.X-from.X), float64(to.Y-from.Y)
+	return int(math.Round(math.Hypot(dx, dy) * float64(a.config.OrthogonalCost)))
+}
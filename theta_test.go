@@ -0,0 +1,95 @@
+package astar
+
+import "testing"
+
+func TestAstar_FindPathTheta(t *testing.T) {
+
+	// [ ] [ ] [ ] [ ] [ ] [ ] [E]   S: StartNode
+	// [ ] [ ] [ ] [ ] [ ] [ ] [ ]   E: EndNode
+	// [ ] [ ] [O] [O] [O] [O] [ ]   O: ObstacleNode
+	// [ ] [ ] [ ] [ ] [ ] [O] [ ]
+	// [ ] [ ] [ ] [ ] [ ] [O] [ ]
+	// [ ] [ ] [ ] [ ] [ ] [O] [ ]
+	// [S] [ ] [ ] [ ] [ ] [ ] [ ]
+
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 6, Y: 6}
+	obstacleNodes := []Node{
+		{X: 2, Y: 4},
+		{X: 3, Y: 4},
+		{X: 4, Y: 4},
+		{X: 5, Y: 4},
+		{X: 5, Y: 3},
+		{X: 5, Y: 2},
+		{X: 5, Y: 1},
+	}
+
+	a, err := New(Config{GridWidth: 7, GridHeight: 7, InvalidNodes: obstacleNodes, AllowDiagonal: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	_, gridCost, err := a.FindPathWithCost(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+
+	foundPath, err := a.FindPathTheta(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if foundPath[0].X != startNode.X || foundPath[0].Y != startNode.Y {
+		t.Error("path should start at the start node: ", foundPath)
+	}
+	last := foundPath[len(foundPath)-1]
+	if last.X != endNode.X || last.Y != endNode.Y {
+		t.Error("path should end at the end node: ", foundPath)
+	}
+	if last.g >= gridCost {
+		t.Error("any-angle path should be shorter than the grid path: ", last.g, gridCost)
+	}
+
+	// every segment is a clear line
+	for i := 1; i < len(foundPath); i++ {
+		if !a.lineOfSight(nil, foundPath[i-1], foundPath[i]) {
+			t.Error("segment crosses an obstacle: ", foundPath[i-1], foundPath[i])
+		}
+	}
+	if len(foundPath) == 2 {
+		t.Error("the direct line crosses the wall: ", foundPath)
+	}
+
+	// blocked end node
+	if _, err = a.FindPathTheta(nil, startNode, Node{X: 5, Y: 2}); err != ErrEndBlocked {
+		t.Error("end node should be blocked", err)
+	}
+}
+
+func TestAstar_FindPathThetaOpenGrid(t *testing.T) {
+	startNode := Node{X: 0, Y: 0}
+	endNode := Node{X: 6, Y: 2}
+
+	a, err := New(Config{GridWidth: 7, GridHeight: 7, AllowDiagonal: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+
+	// a free line is a single segment with the euclidean length
+	foundPath, err := a.FindPathTheta(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path", err)
+	}
+	if len(foundPath) != 2 || foundPath[1].g != 63 {
+		t.Error("path should be the direct line: ", foundPath)
+	}
+
+	// without diagonal movement it falls back to FindPath
+	a, err = New(Config{GridWidth: 7, GridHeight: 7})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	wantPath, _ := a.FindPath(nil, startNode, endNode)
+	foundPath, err = a.FindPathTheta(nil, startNode, endNode)
+	if err != nil || len(foundPath) != len(wantPath) {
+		t.Error("4-way grid should use FindPath", err, foundPath)
+	}
+}
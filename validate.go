@@ -0,0 +1,125 @@
+package astar
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ConfigError is returned by New and Reconfigure if Validate found several problems,
+// a single problem is returned as it is
+type ConfigError struct {
+	// Errors are the problems in the order of Validate
+	Errors []error
+}
+
+// Error returns the messages of all problems
+func (e *ConfigError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return "invalid config: " + strings.Join(messages, ", ")
+}
+
+// Unwrap returns the first problem
+func (e *ConfigError) Unwrap() error {
+	return e.Errors[0]
+}
+
+// validationError returns nil, the only problem or a *ConfigError with all of them
+func validationError(errs []error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
+	return &ConfigError{Errors: errs}
+}
+
+// Validate returns all problems of the config, nil if there are none, e.g. to check maps in tooling
+//
+// The settings come first, then the map data: InvalidNodes, WeightedNodes and BlockedEdges
+// outside of the grid (unless the grid is Unbounded) and WeightedNodes which are InvalidNodes
+// as well, their Weighting never applies. New and Reconfigure fail on all of them
+func (c Config) Validate() []error {
+	errs := c.settingErrors()
+
+	inBounds := func(node Node) bool {
+		if c.Unbounded {
+			return true
+		}
+		x, y := node.X-c.OriginX, node.Y-c.OriginY
+		return x >= 0 && y >= 0 && x < c.GridWidth && y < c.GridHeight
+	}
+	invalid := make(map[[2]int]struct{}, len(c.InvalidNodes))
+	for i, node := range c.InvalidNodes {
+		if !inBounds(node) {
+			errs = append(errs, fmt.Errorf("InvalidNodes[%d] at %d/%d is out of bounds", i, node.X, node.Y))
+		}
+		invalid[nodeKey(node)] = struct{}{}
+	}
+	for i, node := range c.WeightedNodes {
+		if !inBounds(node) {
+			errs = append(errs, fmt.Errorf("WeightedNodes[%d] at %d/%d is out of bounds", i, node.X, node.Y))
+		}
+		if _, ok := invalid[nodeKey(node)]; ok {
+			errs = append(errs, fmt.Errorf("WeightedNodes[%d] at %d/%d is an InvalidNode as well", i, node.X, node.Y))
+		}
+	}
+	for i, edge := range c.BlockedEdges {
+		if !inBounds(edge.From) || !inBounds(edge.To) {
+			errs = append(errs, fmt.Errorf("BlockedEdges[%d] from %d/%d to %d/%d is out of bounds", i, edge.From.X, edge.From.Y, edge.To.X, edge.To.Y))
+		}
+	}
+	return errs
+}
+
+// settingErrors returns the problems of the settings of the config
+func (c Config) settingErrors() []error {
+	var errs []error
+	if !c.Unbounded && c.GridWidth < 1 {
+		errs = append(errs, ErrGridWidth)
+	}
+	if !c.Unbounded && c.GridHeight < 1 {
+		errs = append(errs, ErrGridHeight)
+	}
+	if c.DiagonalPolicy < DiagonalAlways || c.DiagonalPolicy > DiagonalNoCornerCutting {
+		errs = append(errs, errors.New("DiagonalPolicy is unknown"))
+	}
+	if c.DisallowCornerCutting && c.DiagonalPolicy == DiagonalNoCornerCutting {
+		errs = append(errs, errors.New("DisallowCornerCutting and DiagonalNoCornerCutting cannot be combined"))
+	}
+	if c.OrthogonalCost < 0 || c.DiagonalCost < 0 {
+		errs = append(errs, errors.New("OrthogonalCost and DiagonalCost must not be negative"))
+	}
+	if c.BucketOpenList && c.OpenListFactory != nil {
+		errs = append(errs, errors.New("BucketOpenList and OpenListFactory cannot be combined"))
+	}
+	if c.MinMoveCost < 0 {
+		errs = append(errs, errors.New("MinMoveCost must not be negative"))
+	}
+	if c.AgentRadius < 0 {
+		errs = append(errs, errors.New("AgentRadius must not be negative"))
+	}
+	if c.Unbounded && c.AgentRadius > 0 {
+		errs = append(errs, errors.New("AgentRadius needs a bounded grid"))
+	}
+	if c.MaxOpenNodes < 0 {
+		errs = append(errs, errors.New("MaxOpenNodes must not be negative"))
+	}
+	if c.TurnPenalty < 0 {
+		errs = append(errs, errors.New("TurnPenalty must not be negative"))
+	}
+	if c.CellWidth < 0 || c.CellHeight < 0 {
+		errs = append(errs, errors.New("CellWidth and CellHeight must not be negative"))
+	}
+	if c.GridType == GridHex && (c.CellWidth != 0 || c.CellHeight != 0) {
+		errs = append(errs, errors.New("CellWidth and CellHeight need square cells"))
+	}
+	if c.Unbounded && (c.WrapEdges || c.RegionCheck) {
+		errs = append(errs, errors.New("WrapEdges and RegionCheck need a bounded grid"))
+	}
+	return errs
+}
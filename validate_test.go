@@ -0,0 +1,59 @@
+package astar
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	config := Config{GridWidth: 4, GridHeight: 4}
+	if errs := config.Validate(); len(errs) != 0 {
+		t.Error("an empty grid should be valid: ", errs)
+	}
+
+	config = Config{
+		GridWidth:     4,
+		GridHeight:    4,
+		InvalidNodes:  []Node{{X: 1, Y: 1}, {X: 4, Y: 0}},
+		WeightedNodes: []Node{{X: 1, Y: 1, Weighting: 3}, {X: 2, Y: 2, Weighting: 1}, {X: -1, Y: 2, Weighting: 1}},
+		BlockedEdges:  []Edge{{From: Node{X: 3, Y: 3}, To: Node{X: 3, Y: 4}}},
+	}
+	errs := config.Validate()
+	if len(errs) != 4 {
+		t.Fatal("there should be 4 problems: ", errs)
+	}
+	// New reports all of them
+	_, err := New(config)
+	var configErr *ConfigError
+	if !errors.As(err, &configErr) || len(configErr.Errors) != 4 {
+		t.Error("New should fail with all problems", err)
+	}
+
+	// the settings come first
+	config.TurnPenalty = -1
+	errs = config.Validate()
+	if len(errs) != 5 || errs[0].Error() != "TurnPenalty must not be negative" {
+		t.Error("the setting should be the first problem: ", errs)
+	}
+	if _, err := New(config); !errors.As(err, &configErr) || configErr.Unwrap().Error() != errs[0].Error() {
+		t.Error("New should fail with the setting problem first", err)
+	}
+}
+
+func TestNew_InvalidNodeOutOfBounds(t *testing.T) {
+	config := Config{GridWidth: 4, GridHeight: 4, InvalidNodes: []Node{{X: 1, Y: 1}, {X: 4, Y: 2}}}
+	if _, err := New(config); err == nil || err.Error() != "InvalidNodes[1] at 4/2 is out of bounds" {
+		t.Error("New should reject the InvalidNode outside of the grid", err)
+	}
+
+	a, err := New(Config{GridWidth: 4, GridHeight: 4})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if err := a.Reconfigure(config); err == nil {
+		t.Error("Reconfigure should reject the InvalidNode outside of the grid")
+	}
+	if foundPath, err := a.FindPath(nil, Node{X: 0, Y: 0}, Node{X: 1, Y: 1}); err != nil || len(foundPath) != 3 {
+		t.Error("the old config should stay in place", err, foundPath)
+	}
+}
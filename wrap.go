@@ -0,0 +1,50 @@
+package astar
+
+// wrapXY maps the coordinates onto the grid
+// by adding or removing the grid size, used with WrapEdges
+func (a *PathFinder) wrapXY(x, y int) (int, int) {
+	return a.config.OriginX + modInt(x-a.config.OriginX, a.config.GridWidth),
+		a.config.OriginY + modInt(y-a.config.OriginY, a.config.GridHeight)
+}
+
+// stepDelta returns the X/Y change of the step between two neighbors,
+// with WrapEdges a step over the seam is short instead of a jump over the whole grid
+func (a *PathFinder) stepDelta(from, to Node) [2]int {
+	dx, dy := to.X-from.X, to.Y-from.Y
+	if a.config.WrapEdges {
+		if dx = modInt(dx, a.config.GridWidth); dx > a.config.GridWidth/2 {
+			dx -= a.config.GridWidth
+		}
+		if dy = modInt(dy, a.config.GridHeight); dy > a.config.GridHeight/2 {
+			dy -= a.config.GridHeight
+		}
+	}
+	return [2]int{dx, dy}
+}
+
+// wrappedDistance returns the smallest distance between nodeA and the
+// copies of nodeB left, right, below and above the grid
+func (a *PathFinder) wrappedDistance(nodeA, nodeB Node, distance func(nodeA, nodeB Node) int) int {
+	dx := modInt(nodeB.X-nodeA.X, a.config.GridWidth)
+	dy := modInt(nodeB.Y-nodeA.Y, a.config.GridHeight)
+
+	minDistance := -1
+	for _, offsetX := range [2]int{dx, dx - a.config.GridWidth} {
+		for _, offsetY := range [2]int{dy, dy - a.config.GridHeight} {
+			d := distance(nodeA, Node{X: nodeA.X + offsetX, Y: nodeA.Y + offsetY})
+			if minDistance < 0 || d < minDistance {
+				minDistance = d
+			}
+		}
+	}
+	return minDistance
+}
+
+// modInt returns the non negative remainder of value / size
+func modInt(value, size int) int {
+	value %= size
+	if value < 0 {
+		value += size
+	}
+	return value
+}
@@ -0,0 +1,107 @@
+package astar
+
+import "testing"
+
+func TestAstar_FindPathWrapEdges(t *testing.T) {
+
+	// [ ] [ ] [O] [ ] [ ]   S: StartNode
+	// [S] [ ] [O] [ ] [E]   E: EndNode
+	// [ ] [ ] [O] [ ] [ ]   O: ObstacleNode
+
+	startNode := Node{X: 0, Y: 1}
+	endNode := Node{X: 4, Y: 1}
+	obstacleNodes := []Node{{X: 2, Y: 0}, {X: 2, Y: 1}, {X: 2, Y: 2}}
+
+	a, err := New(Config{GridWidth: 5, GridHeight: 3, InvalidNodes: obstacleNodes})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	if _, err = a.FindPath(nil, startNode, endNode); err != ErrorNoPath {
+		t.Error("the wall should split the grid", err)
+	}
+
+	a, err = New(Config{GridWidth: 5, GridHeight: 3, InvalidNodes: obstacleNodes, WrapEdges: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, err := a.FindPath(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path over the seam", err)
+	}
+	if len(foundPath) != 2 || foundPath[1].X != endNode.X || foundPath[1].Y != endNode.Y {
+		t.Error("path should step over the left border: ", foundPath)
+	}
+
+	directions, err := a.FindPathDirections(nil, startNode, endNode)
+	if err != nil || len(directions) != 1 || directions[0] != DirLeft {
+		t.Error("the step over the seam should be a move to the left: ", directions, err)
+	}
+
+	// the top and the bottom row are neighbors as well
+	foundPath, err = a.FindPath(nil, Node{X: 0, Y: 0}, Node{X: 0, Y: 2})
+	if err != nil || len(foundPath) != 2 {
+		t.Error("path should step over the bottom border: ", foundPath, err)
+	}
+}
+
+func TestAstar_HWrapEdges(t *testing.T) {
+	a, err := New(Config{GridWidth: 10, GridHeight: 8, WrapEdges: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+
+	tests := []struct {
+		nodeA, nodeB Node
+		want         int
+	}{
+		{Node{X: 0, Y: 0}, Node{X: 9, Y: 0}, 1},
+		{Node{X: 1, Y: 1}, Node{X: 8, Y: 7}, 5},
+		{Node{X: 2, Y: 2}, Node{X: 4, Y: 3}, 3},
+		{Node{X: 0, Y: 0}, Node{X: 5, Y: 4}, 9},
+	}
+	for _, test := range tests {
+		if h := a.H(test.nodeA, test.nodeB); h != test.want {
+			t.Error("wrong wrapped distance: ", test.nodeA, test.nodeB, h, test.want)
+		}
+		if h := a.H(test.nodeB, test.nodeA); h != test.want {
+			t.Error("wrapped distance should be symmetric: ", test.nodeB, test.nodeA, h, test.want)
+		}
+	}
+}
+
+func TestAstar_FindPathWrapEdgesNegativeOrigin(t *testing.T) {
+
+	// [ ] [O] [ ] [ ]    y = 0    S: StartNode
+	// [S] [O] [ ] [E]    y = -1   E: EndNode
+	// [ ] [O] [ ] [ ]    y = -2   O: ObstacleNode
+	// [O] [O] [ ] [O]    y = -3
+	// x = -4 ... -1
+
+	startNode := Node{X: -4, Y: -1}
+	endNode := Node{X: -1, Y: -1}
+	obstacleNodes := []Node{
+		{X: -3, Y: 0}, {X: -3, Y: -1}, {X: -3, Y: -2}, {X: -3, Y: -3},
+		{X: -4, Y: -3}, {X: -1, Y: -3},
+	}
+
+	a, err := New(Config{GridWidth: 4, GridHeight: 4, OriginX: -4, OriginY: -3, InvalidNodes: obstacleNodes, WrapEdges: true})
+	if err != nil {
+		t.Fatal("there should be no error", err)
+	}
+	foundPath, err := a.FindPath(nil, startNode, endNode)
+	if err != nil {
+		t.Fatal("there should be a path over the seam", err)
+	}
+	if len(foundPath) != 2 || foundPath[1].X != endNode.X || foundPath[1].Y != endNode.Y {
+		t.Error("path should step over the left border: ", foundPath)
+	}
+
+	// (-2, -3) is free while (-4, -3) and (-1, -3) are blocked
+	foundPath, err = a.FindPath(nil, Node{X: -2, Y: 0}, Node{X: -2, Y: -3})
+	if err != nil || len(foundPath) != 2 {
+		t.Error("path should step over the top border: ", foundPath, err)
+	}
+	if _, err = a.FindPath(nil, startNode, Node{X: -4, Y: -3}); err != ErrEndBlocked {
+		t.Error("the blocked corner should stay blocked", err)
+	}
+}